@@ -2,9 +2,9 @@ module "main_module" {
   source     = "../../../modules/default"
   is_enabled = var.is_enabled
 
-  tags = {
+  tags = merge({
     environment = "development"
     project     = "terraform-module-template"
     managed-by  = "terraform"
-  }
+  }, var.tags)
 }
@@ -2,9 +2,9 @@ module "main_module" {
   source     = "../../../modules/default"
   is_enabled = false
 
-  tags = {
+  tags = merge({
     Environment = "development"
     Terraform   = "true"
     Module      = "default"
-  }
+  }, var.tags)
 }
@@ -1,4 +1,6 @@
-data "aws_caller_identity" "current" {}
+data "aws_caller_identity" "current" {
+  count = var.is_enabled ? 1 : 0
+}
 data "aws_partition" "current" {}
 
 resource "aws_codeartifact_domain" "example" {
@@ -18,19 +20,14 @@ module "this" {
   role_path        = var.role_path
 
   # Trust policy configuration (object-based, default)
-  # external_principals = var.external_principals
-
-  # Uncomment below to use ARN-based trust policy override instead
-  external_principals_arns_override = [
-    "arn:aws:iam::111122223333:role/dpca-basic-role-example",
-    "arn:aws:iam::444455556666:role/dpca-basic-role-example"
-  ]
+  external_principals = var.external_principals
 
-  external_principals = []
+  # ARN-based trust policy; takes precedence over external_principals when set
+  external_principals_arns_override = var.external_principals_arns_override
 
   iam_role_cross_account_policies = [
     {
-      name = "CodeArtifactReadOnlyToken"
+      name = "${var.role_name}-token"
       policy = jsonencode({
         Version = "2012-10-17"
         Statement = [
@@ -38,12 +35,21 @@ module "this" {
             Effect   = "Allow"
             Action   = ["codeartifact:GetAuthorizationToken"]
             Resource = var.is_enabled ? aws_codeartifact_domain.example[0].arn : "*"
+          },
+          {
+            # CodeArtifact tokens are issued through an STS service bearer token
+            Effect   = "Allow"
+            Action   = ["sts:GetServiceBearerToken"]
+            Resource = "*"
+            Condition = {
+              StringEquals = { "sts:AWSServiceName" = "codeartifact.amazonaws.com" }
+            }
           }
         ]
       })
     },
     {
-      name = "CodeArtifactReadOnlyAccess"
+      name = "${var.role_name}-read"
       policy = jsonencode({
         Version = "2012-10-17"
         Statement = [
@@ -60,8 +66,6 @@ module "this" {
     }
   ]
 
-
-
   tags = var.tags
 
   depends_on = [aws_codeartifact_domain.example]
@@ -3,19 +3,21 @@
 # Input variables defined in variables.tf are passed to the module, often controlled by fixtures.
 
 # Get the current AWS account identity for default owner calculation
-data "aws_caller_identity" "current" {}
+data "aws_caller_identity" "current" {
+  count = var.is_enabled ? 1 : 0
+}
 
 # Create a temporary CodeArtifact domain for this example run
 resource "aws_codeartifact_domain" "this" {
   count  = var.is_enabled ? 1 : 0
   domain = "example-domain-${var.domain_name_suffix}" # Use suffix for uniqueness
 
-  tags = {
+  tags = merge({
     Environment = "development"
     ManagedBy   = "terraform"
     Example     = "domain-permissions-dynamic"
     Temporary   = "true" # Indicate it's for testing
-  }
+  }, var.tags)
 }
 
 # Call the domain-permissions module
@@ -31,7 +33,7 @@ module "this" {
   domain_name = aws_codeartifact_domain.this[0].domain
 
   # Optional: Use explicit owner from var or default to current account
-  domain_owner = coalesce(var.domain_owner, data.aws_caller_identity.current.account_id)
+  domain_owner = coalesce(var.domain_owner, data.aws_caller_identity.current[0].account_id)
 
   # Pass through dynamic policy variables (controlled by fixtures)
   read_principals                = var.read_principals
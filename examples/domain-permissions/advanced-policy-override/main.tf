@@ -3,7 +3,9 @@
 # It creates a CodeArtifact domain and applies a completely custom policy to it.
 
 # Get the current AWS account identity
-data "aws_caller_identity" "current" {}
+data "aws_caller_identity" "current" {
+  count = var.is_enabled ? 1 : 0
+}
 
 # Create a CodeArtifact domain for testing this example
 # Note: The domain name used here should match the one used in the override policy JSON
@@ -11,11 +13,11 @@ resource "aws_codeartifact_domain" "this" {
   count  = var.is_enabled ? 1 : 0
   domain = var.domain_name # Provided by fixture
 
-  tags = {
+  tags = merge({
     Environment = "development"
     ManagedBy   = "terraform"
     Example     = "advanced-policy-override"
-  }
+  }, var.tags)
 }
 
 # Define the override policy document using a data source
@@ -28,7 +30,7 @@ data "aws_iam_policy_document" "override_policy" {
     principals {
       type = "AWS"
       # Use the current account executing Terraform
-      identifiers = ["arn:aws:iam::${data.aws_caller_identity.current.account_id}:root"]
+      identifiers = ["arn:aws:iam::${data.aws_caller_identity.current[0].account_id}:root"]
     }
     actions = [
       "codeartifact:ListRepositoriesInDomain",
@@ -37,7 +39,7 @@ data "aws_iam_policy_document" "override_policy" {
     # For the example, use a hardcoded ARN format instead of the actual domain ARN
     # This breaks the dependency cycle
     resources = var.is_enabled ? [
-      "arn:aws:codeartifact:${var.aws_region}:${data.aws_caller_identity.current.account_id}:domain/${var.domain_name}"
+      "arn:aws:codeartifact:${var.aws_region}:${data.aws_caller_identity.current[0].account_id}:domain/${var.domain_name}"
     ] : []
   }
 }
@@ -56,7 +58,7 @@ module "this" {
   # Core inputs for the module
   is_enabled   = var.is_enabled
   domain_name  = var.domain_name
-  domain_owner = var.domain_owner != null ? var.domain_owner : data.aws_caller_identity.current.account_id
+  domain_owner = var.domain_owner != null ? var.domain_owner : data.aws_caller_identity.current[0].account_id
 
   # --- Policy Override ---
   # Provide the override policy document directly from the local variable
@@ -54,3 +54,9 @@ variable "policy_revision" {
   type        = string
   default     = null
 }
+
+variable "tags" {
+  description = "Additional tags to apply to all resources created by this example."
+  type        = map(string)
+  default     = {}
+}
@@ -11,7 +11,9 @@
 # `terraform plan` can be run without the domain existing to see the generated policy.
 
 # Get the current AWS account identity
-data "aws_caller_identity" "current" {}
+data "aws_caller_identity" "current" {
+  count = var.is_enabled ? 1 : 0
+}
 
 # Create a CodeArtifact domain for testing
 resource "aws_codeartifact_domain" "this" {
@@ -22,11 +24,11 @@ resource "aws_codeartifact_domain" "this" {
   # Uncomment if you need custom encryption
   # encryption_key = aws_kms_key.example.arn
 
-  tags = {
+  tags = merge({
     Environment = "development"
     ManagedBy   = "terraform"
     Example     = "domain-permissions-basic"
-  }
+  }, var.tags)
 }
 
 module "this" {
@@ -37,9 +39,9 @@ module "this" {
   # Pass through variables controlled by the example/fixtures
   is_enabled   = var.is_enabled
   domain_name  = join("", [for d in aws_codeartifact_domain.this : d.domain])
-  domain_owner = var.domain_owner != "" ? var.domain_owner : data.aws_caller_identity.current.account_id
+  domain_owner = var.domain_owner != "" ? var.domain_owner : data.aws_caller_identity.current[0].account_id
   # Use the current account ID for read_principals if none provided
-  read_principals                = length(var.read_principals) > 0 ? var.read_principals : ["arn:aws:iam::${data.aws_caller_identity.current.account_id}:root"]
+  read_principals                = length(var.read_principals) > 0 ? var.read_principals : ["arn:aws:iam::${data.aws_caller_identity.current[0].account_id}:root"]
   list_repo_principals           = var.list_repo_principals
   authorization_token_principals = var.authorization_token_principals
   custom_policy_statements       = var.custom_policy_statements
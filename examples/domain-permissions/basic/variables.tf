@@ -58,3 +58,9 @@ variable "policy_document_override" {
   type        = string
   default     = null
 }
+
+variable "tags" {
+  description = "Additional tags to apply to all resources created by this example."
+  type        = map(string)
+  default     = {}
+}
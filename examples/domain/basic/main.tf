@@ -22,7 +22,7 @@ resource "aws_kms_key" "this" {
         Sid    = "Enable IAM User Permissions"
         Effect = "Allow"
         Principal = {
-          AWS = "arn:aws:iam::${data.aws_caller_identity.current.account_id}:root"
+          AWS = "arn:aws:iam::${data.aws_caller_identity.current[0].account_id}:root"
         }
         Action   = "kms:*"
         Resource = "*"
@@ -45,10 +45,10 @@ resource "aws_kms_key" "this" {
     ]
   })
 
-  tags = {
+  tags = merge({
     Name        = "${local.name}-kms-key"
     Environment = local.environment
-  }
+  }, var.tags)
 }
 
 ################################################################################
@@ -59,7 +59,7 @@ module "this" {
   source = "../../../modules/domain"
 
   is_enabled   = var.is_enabled
-  domain_name  = "${local.name}-domain"
+  domain_name  = var.domain_name
   kms_key_arn  = var.use_default_kms ? null : try(aws_kms_key.this[0].arn, null)
   domain_owner = var.domain_owner
 
@@ -76,22 +76,24 @@ module "this" {
         ]
         Effect = "Allow"
         Principal = {
-          AWS = data.aws_caller_identity.current.account_id
+          AWS = try(data.aws_caller_identity.current[0].account_id, null)
         }
         Resource = "*"
       }
     ]
   })
 
-  tags = {
+  tags = merge({
     Environment = local.environment
     Terraform   = "true"
     Example     = "basic"
-  }
+  }, var.tags)
 }
 
 ################################################################################
 # Supporting Resources
 ################################################################################
 
-data "aws_caller_identity" "current" {}
+data "aws_caller_identity" "current" {
+  count = var.is_enabled ? 1 : 0
+}
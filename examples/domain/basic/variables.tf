@@ -4,6 +4,12 @@ variable "is_enabled" {
   default     = true
 }
 
+variable "domain_name" {
+  description = "The name of the CodeArtifact domain to create."
+  type        = string
+  default     = "example-domain"
+}
+
 variable "enable_domain_permissions_policy" {
   description = "Controls whether to create a domain permissions policy."
   type        = bool
@@ -27,3 +33,9 @@ variable "aws_region" {
   type        = string
   default     = "us-west-2"
 }
+
+variable "tags" {
+  description = "Additional tags to apply to all resources created by this example."
+  type        = map(string)
+  default     = {}
+}
@@ -30,20 +30,21 @@ module "this" {
   codeartifact_domain_name = var.codeartifact_domain_name
 
   # --- OIDC Provider Inputs ---
-  is_oidc_provider_enabled = var.is_oidc_provider_enabled
-  oidc_provider_url        = var.oidc_provider_url
-  oidc_client_id_list      = var.oidc_client_id_list
-  oidc_thumbprint_list     = var.oidc_thumbprint_list
+  is_oidc_provider_enabled   = var.is_oidc_provider_enabled
+  oidc_use_existing_provider = var.oidc_use_existing_provider
+  oidc_provider_url          = var.oidc_provider_url
+  oidc_client_id_list        = var.oidc_client_id_list
+  oidc_thumbprint_list       = var.oidc_thumbprint_list
 
   # --- OIDC Roles Input ---
   # Pass the list of role configurations defined in variables/fixtures
   oidc_roles = var.oidc_roles
 
   # Common Tags
-  tags = {
+  tags = merge({
     Environment = var.environment
     Project     = "terraform-aws-codeartifact"
     ManagedBy   = "terraform"
     Example     = "advanced-oidc"
-  }
+  }, var.tags)
 }
@@ -135,6 +135,12 @@ variable "is_oidc_provider_enabled" {
   default     = true # Default to enabled in the advanced example
 }
 
+variable "oidc_use_existing_provider" {
+  description = "Pass-through for module's oidc_use_existing_provider."
+  type        = bool
+  default     = false
+}
+
 variable "oidc_provider_url" {
   description = "Pass-through for module's oidc_provider_url."
   type        = string
@@ -165,3 +171,9 @@ variable "oidc_roles" {
   }))
   default = [] # Must be set in fixture
 }
+
+variable "tags" {
+  description = "Additional tags to apply to all resources created by this example."
+  type        = map(string)
+  default     = {}
+}
@@ -3,9 +3,15 @@
 ###################################
 
 data "aws_caller_identity" "current" {
+  count    = var.is_enabled ? 1 : 0
   provider = aws.source
 }
 
+locals {
+  # The replica bucket name embeds the account ID, which is only read when the example is enabled
+  replica_bucket_name = var.is_enabled ? "${var.replica_bucket_name}-${data.aws_caller_identity.current[0].account_id}-${var.replica_region}" : var.replica_bucket_name
+}
+
 ###################################
 # Foundation Module Call (Replica Bucket) 🚀 (Using Replica Region Provider)
 # ----------------------------------------------------
@@ -29,7 +35,7 @@ module "replica_foundation" {
 
   # --- S3 Bucket Configuration ---
   # Use a unique name for the replica bucket
-  s3_bucket_name = "${var.replica_bucket_name}-${data.aws_caller_identity.current.account_id}-${var.replica_region}"
+  s3_bucket_name = local.replica_bucket_name
 
   # --- Other Foundation Inputs (using example vars/defaults or specific replica values) ---
   # Provide dummy values for required inputs of disabled features if necessary,
@@ -41,7 +47,7 @@ module "replica_foundation" {
   # Common Tags
   tags = merge(
     var.tags,
-    { Name = "${var.replica_bucket_name}-${data.aws_caller_identity.current.account_id}-${var.replica_region}" }
+    { Name = local.replica_bucket_name }
   )
 }
 
@@ -22,17 +22,18 @@ module "this" {
   # CodeArtifact domain configuration
   codeartifact_domain_name = var.codeartifact_domain_name
 
-  tags = {
+  tags = merge({
     Environment = var.environment
     Project     = "terraform-aws-codeartifact"
     ManagedBy   = "terraform"
     Example     = "basic"
-  }
+  }, var.tags)
 
   # --- OIDC Provider Inputs ---
-  is_oidc_provider_enabled = var.is_oidc_provider_enabled
-  oidc_provider_url        = var.oidc_provider_url
-  oidc_client_id_list      = var.oidc_client_id_list
-  oidc_thumbprint_list     = var.oidc_thumbprint_list
-  oidc_roles               = var.oidc_roles # Pass the list of role configurations
+  is_oidc_provider_enabled   = var.is_oidc_provider_enabled
+  oidc_use_existing_provider = var.oidc_use_existing_provider
+  oidc_provider_url          = var.oidc_provider_url
+  oidc_client_id_list        = var.oidc_client_id_list
+  oidc_thumbprint_list       = var.oidc_thumbprint_list
+  oidc_roles                 = var.oidc_roles # Pass the list of role configurations
 }
@@ -107,6 +107,12 @@ variable "is_oidc_provider_enabled" {
   default     = false # Default to disabled in the basic example
 }
 
+variable "oidc_use_existing_provider" {
+  description = "Pass-through for module's oidc_use_existing_provider."
+  type        = bool
+  default     = false
+}
+
 variable "oidc_provider_url" {
   description = "Pass-through for module's oidc_provider_url."
   type        = string
@@ -137,3 +143,9 @@ variable "oidc_roles" {
   }))
   default = [] # Default to no roles in the basic example unless specified in fixtures
 }
+
+variable "tags" {
+  description = "Additional tags to apply to all resources created by this example."
+  type        = map(string)
+  default     = {}
+}
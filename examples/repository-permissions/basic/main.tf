@@ -1,4 +1,6 @@
-data "aws_caller_identity" "current" {}
+data "aws_caller_identity" "current" {
+  count = var.is_enabled ? 1 : 0
+}
 
 resource "aws_codeartifact_domain" "example" {
   count = var.is_enabled ? 1 : 0
@@ -25,12 +27,12 @@ module "this" {
 
   source = "../../../modules/repository-permissions"
 
-  domain_name     = module.repository[0].repository_domain_name
+  domain_name     = aws_codeartifact_domain.example[0].domain
   repository_name = module.repository[0].repository_name
   domain_owner    = module.repository[0].repository_domain_owner # Pass domain owner from repo module output
 
-  # Grant read access to specified principals, or default to the current caller
-  read_principals = length(var.policy_principals) > 0 ? var.policy_principals : [data.aws_caller_identity.current.arn]
+  # Grant read access to specified principals, or default to the current account
+  read_principals = length(var.policy_principals) > 0 ? var.policy_principals : ["arn:aws:iam::${data.aws_caller_identity.current[0].account_id}:root"]
 
   # No other baseline principals for basic example
   describe_principals            = []
@@ -43,7 +45,7 @@ module "this" {
       Effect = "Allow",
       Principal = {
         Type        = "AWS"
-        Identifiers = [data.aws_caller_identity.current.arn]
+        Identifiers = [data.aws_caller_identity.current[0].arn]
       },
       Action = [
         "codeartifact:PublishPackageVersion",
@@ -66,7 +68,7 @@ module "this" {
         # "codeartifact:UpdatePackageVersionsStatus",
         # "codeartifact:UpdateRepository"
       ],
-      Resource = "*" # Resource is typically "*" in repository policies
+      Resource = ["*"] # Resource is typically "*" in repository policies
     }
   ]
 
@@ -25,21 +25,21 @@ output "repository_module_repository_name" {
 
 output "repository_permissions_module_is_enabled" {
   description = "Indicates whether the repository permissions policy resource was enabled."
-  value       = module.this.is_enabled # Note: module.this refers to repository-permissions module
+  value       = var.is_enabled ? module.this[0].is_enabled : false # Note: module.this refers to repository-permissions module
 }
 
 output "repository_permissions_module_policy_document" {
   description = "The generated JSON policy document applied to the repository."
-  value       = module.this.policy_document
+  value       = var.is_enabled ? module.this[0].policy_document : null
   sensitive   = true # Policy documents can contain sensitive info
 }
 
 output "repository_permissions_module_policy_revision" {
   description = "The current revision of the repository permissions policy."
-  value       = module.this.policy_revision
+  value       = var.is_enabled ? module.this[0].policy_revision : null
 }
 
 output "repository_permissions_module_resource_arn" {
   description = "The ARN of the repository permissions policy resource."
-  value       = module.this.resource_arn
+  value       = var.is_enabled ? module.this[0].resource_arn : null
 }
@@ -3,7 +3,9 @@
 # 2. A downstream repository that uses the first as an upstream, has external connections, and has a policy attached.
 # It creates a self-contained example including the domain.
 
-data "aws_caller_identity" "current" {}
+data "aws_caller_identity" "current" {
+  count = var.is_enabled ? 1 : 0
+}
 
 # Create a CodeArtifact domain for the repositories
 resource "aws_codeartifact_domain" "this" {
@@ -43,7 +45,7 @@ data "aws_iam_policy_document" "repository_policy" {
     principals {
       type = "AWS"
       # Use provided principal ARN or default to the current caller
-      identifiers = [coalesce(var.policy_principal_arn, data.aws_caller_identity.current.arn)]
+      identifiers = [coalesce(var.policy_principal_arn, data.aws_caller_identity.current[0].arn)]
     }
     actions = [
       "codeartifact:ReadFromRepository",
@@ -56,7 +58,7 @@ data "aws_iam_policy_document" "repository_policy" {
     ]
     # Construct the downstream repository ARN dynamically
     resources = [
-      "arn:aws:codeartifact:${var.aws_region}:${data.aws_caller_identity.current.account_id}:repository/${var.domain_name}/${var.downstream_repo_name}"
+      "arn:aws:codeartifact:${var.aws_region}:${data.aws_caller_identity.current[0].account_id}:repository/${var.domain_name}/${var.downstream_repo_name}"
     ]
   }
 
@@ -66,7 +68,7 @@ data "aws_iam_policy_document" "repository_policy" {
     principals {
       type = "AWS"
       # Use provided principal ARN or default to the current caller
-      identifiers = [coalesce(var.policy_principal_arn, data.aws_caller_identity.current.arn)]
+      identifiers = [coalesce(var.policy_principal_arn, data.aws_caller_identity.current[0].arn)]
     }
     actions   = ["sts:GetCallerIdentity"]
     resources = ["*"] # sts:GetCallerIdentity does not support resource-level permissions
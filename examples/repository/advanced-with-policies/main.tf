@@ -2,7 +2,9 @@
 # using the repository module's `repository_policy_document` input.
 # It creates a self-contained example including the domain and policy document.
 
-data "aws_caller_identity" "current" {}
+data "aws_caller_identity" "current" {
+  count = var.is_enabled ? 1 : 0
+}
 
 # Create a CodeArtifact domain for the repository
 resource "aws_codeartifact_domain" "this" {
@@ -23,7 +25,7 @@ data "aws_iam_policy_document" "repository_policy" {
     principals {
       type = "AWS"
       # Use provided principal ARN or default to the current caller
-      identifiers = [coalesce(var.policy_principal_arn, data.aws_caller_identity.current.arn)]
+      identifiers = [coalesce(var.policy_principal_arn, data.aws_caller_identity.current[0].arn)]
     }
     actions = [
       "codeartifact:ReadFromRepository",
@@ -36,7 +38,7 @@ data "aws_iam_policy_document" "repository_policy" {
     ]
     # Construct the repository ARN dynamically
     resources = [
-      "arn:aws:codeartifact:${var.aws_region}:${data.aws_caller_identity.current.account_id}:repository/${var.domain_name}/${var.repository_name}"
+      "arn:aws:codeartifact:${var.aws_region}:${data.aws_caller_identity.current[0].account_id}:repository/${var.domain_name}/${var.repository_name}"
     ]
   }
 
@@ -46,7 +48,7 @@ data "aws_iam_policy_document" "repository_policy" {
     principals {
       type = "AWS"
       # Use provided principal ARN or default to the current caller
-      identifiers = [coalesce(var.policy_principal_arn, data.aws_caller_identity.current.arn)]
+      identifiers = [coalesce(var.policy_principal_arn, data.aws_caller_identity.current[0].arn)]
     }
     actions   = ["sts:GetCallerIdentity"]
     resources = ["*"] # sts:GetCallerIdentity does not support resource-level permissions
@@ -19,7 +19,7 @@ data "aws_iam_policy_document" "trust_policy" {
       # Format principals correctly as a list of ARNs
       identifiers = [
         for principal in var.external_principals :
-        principal.full_arn_override != "" ? principal.full_arn_override : "arn:${data.aws_partition.current.partition}:iam::${principal.account_id}:role/${principal.role_name}"
+        "arn:${data.aws_partition.current.partition}:iam::${principal.account_id}:role/${principal.role_name}"
       ]
     }
   }
@@ -1,4 +1,7 @@
-data "aws_caller_identity" "current" {}
+data "aws_caller_identity" "current" {
+  # Looked up only when enabled, so a disabled module makes no STS call
+  count = var.is_enabled ? 1 : 0
+}
 
 data "aws_partition" "current" {}
 
@@ -24,7 +27,7 @@ data "aws_iam_policy_document" "combined" {
     ]
     principals {
       type        = "AWS"
-      identifiers = ["arn:${data.aws_partition.current.partition}:iam::${data.aws_caller_identity.current.account_id}:root"] # Grant to the account root executing Terraform
+      identifiers = ["arn:${data.aws_partition.current.partition}:iam::${data.aws_caller_identity.current[0].account_id}:root"] # Grant to the account root executing Terraform
     }
     resources = [local.domain_arn]
   }
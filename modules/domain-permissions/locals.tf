@@ -4,10 +4,10 @@ locals {
   is_built_in_policy_enabled = var.is_enabled && var.policy_document_override == null
 
   # Determine effective owner (current account or specified)
-  effective_domain_owner = coalesce(var.domain_owner, data.aws_caller_identity.current.account_id)
+  effective_domain_owner = local.is_enabled ? coalesce(var.domain_owner, data.aws_caller_identity.current[0].account_id) : null
 
   # Construct the domain ARN needed for policy statements
-  domain_arn = "arn:${data.aws_partition.current.partition}:codeartifact:${data.aws_region.current.name}:${local.effective_domain_owner}:domain/${var.domain_name}"
+  domain_arn = local.is_enabled ? "arn:${data.aws_partition.current.partition}:codeartifact:${data.aws_region.current.name}:${local.effective_domain_owner}:domain/${var.domain_name}" : null
 
   # --- Policy Construction Logic ---
 
@@ -11,7 +11,7 @@ locals {
   domain_permissions_policy = var.domain_permissions_policy
 
   # Use the caller's identity if domain_owner is not provided
-  effective_domain_owner = local.domain_owner != null ? local.domain_owner : data.aws_caller_identity.current.account_id
+  effective_domain_owner = local.domain_owner != null ? local.domain_owner : try(data.aws_caller_identity.current[0].account_id, null)
 
   # Default tags to be merged with user provided tags
   default_tags = {
@@ -6,7 +6,9 @@
 #
 ###################################
 
-data "aws_caller_identity" "current" {}
+data "aws_caller_identity" "current" {
+  count = var.is_enabled ? 1 : 0
+}
 
 ###################################
 # OIDC Data Sources 🔑
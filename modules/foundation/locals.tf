@@ -29,6 +29,9 @@ locals {
   # CodeArtifact domain name for resource naming
   codeartifact_domain_name = var.codeartifact_domain_name
 
+  # Root principal of the current account, granted access by the default KMS key and bucket policies
+  account_root_arn = local.is_enabled ? "arn:aws:iam::${data.aws_caller_identity.current[0].account_id}:root" : null
+
   # KMS key naming and description
   kms_key_description = "KMS key for CodeArtifact ${local.codeartifact_domain_name} domain encryption and backup"
   kms_key_policy = coalesce(var.kms_key_policy, jsonencode({
@@ -38,7 +41,7 @@ locals {
         Sid    = "Enable Limited IAM Root User Permissions"
         Effect = "Allow"
         Principal = {
-          AWS = "arn:aws:iam::${data.aws_caller_identity.current.account_id}:root"
+          AWS = local.account_root_arn
         }
         Action = [
           "kms:Create*",
@@ -123,7 +126,7 @@ locals {
     actions = ["s3:GetObject", "s3:ListBucket"]
     principals = {
       type        = "AWS"
-      identifiers = ["arn:aws:iam::${data.aws_caller_identity.current.account_id}:root"] # Fixed: ARN format
+      identifiers = [local.account_root_arn]
     }
     resources = [
       "arn:aws:s3:::${var.s3_bucket_name}",
@@ -7,12 +7,12 @@ locals {
   # We create the resource if the module is enabled.
   create_policy = local.is_enabled
 
-  # Determine effective owner (current account or specified)
-  effective_domain_owner = coalesce(var.domain_owner, data.aws_caller_identity.current.account_id)
+  # Determine effective owner (current account or specified); the caller identity is only read when enabled
+  effective_domain_owner = local.is_enabled ? coalesce(var.domain_owner, data.aws_caller_identity.current[0].account_id) : null
 
   # Construct the repository ARN needed for policy statements
-  repository_arn = "arn:${data.aws_partition.current.partition}:codeartifact:${data.aws_region.current.name}:${local.effective_domain_owner}:repository/${var.domain_name}/${var.repository_name}"
+  repository_arn = local.is_enabled ? "arn:${data.aws_partition.current.partition}:codeartifact:${data.aws_region.current.name}:${local.effective_domain_owner}:repository/${var.domain_name}/${var.repository_name}" : null
 
   # Construct the domain ARN needed for GetAuthorizationToken action
-  domain_arn = "arn:${data.aws_partition.current.partition}:codeartifact:${data.aws_region.current.name}:${local.effective_domain_owner}:domain/${var.domain_name}"
+  domain_arn = local.is_enabled ? "arn:${data.aws_partition.current.partition}:codeartifact:${data.aws_region.current.name}:${local.effective_domain_owner}:domain/${var.domain_name}" : null
 }
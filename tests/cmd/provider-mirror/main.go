@@ -0,0 +1,185 @@
+// Command provider-mirror populates a filesystem provider mirror with every provider required by
+// the modules and examples of this repository, so tests can run `terraform init` without internet
+// access through helper.WithProviderMirror or TF_TEST_PROVIDER_MIRROR_DIR.
+//
+// Usage:
+//
+//	go run ./cmd/provider-mirror -dir /path/to/mirror [-platform linux_amd64 -platform darwin_arm64]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/repo"
+)
+
+// platforms collects the repeatable -platform flag.
+type platforms []string
+
+func (p *platforms) String() string { return strings.Join(*p, ",") }
+
+func (p *platforms) Set(value string) error {
+	*p = append(*p, value)
+	return nil
+}
+
+func main() {
+	var targetPlatforms platforms
+
+	mirrorDir := flag.String("dir", os.Getenv(helper.ProviderMirrorEnvVar), "directory to populate with providers (defaults to $"+helper.ProviderMirrorEnvVar+")")
+	terraformBinary := flag.String("terraform", "terraform", "Terraform binary used to download providers")
+	dryRun := flag.Bool("dry-run", false, "list the discovered provider requirements without downloading them")
+	flag.Var(&targetPlatforms, "platform", "target platform, e.g. linux_amd64; repeatable (defaults to the current platform)")
+	flag.Parse()
+
+	if *mirrorDir == "" && !*dryRun {
+		log.Fatalf("❌ a mirror directory is required: pass -dir or set %s", helper.ProviderMirrorEnvVar)
+	}
+
+	if err := run(*mirrorDir, *terraformBinary, targetPlatforms, *dryRun); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+}
+
+// run mirrors the providers of every versions.tf discovered under modules/ and examples/.
+func run(mirrorDir, terraformBinary string, targetPlatforms []string, dryRun bool) error {
+	dirs, err := repo.NewTFSourcesDir()
+	if err != nil {
+		return fmt.Errorf("failed to get Terraform sources directory: %w", err)
+	}
+
+	files, err := dirs.ListVersionsFiles()
+	if err != nil {
+		return err
+	}
+
+	if !dryRun {
+		if mirrorDir, err = filepath.Abs(mirrorDir); err != nil {
+			return fmt.Errorf("failed to resolve mirror directory: %w", err)
+		}
+
+		if err := os.MkdirAll(mirrorDir, 0o755); err != nil {
+			return fmt.Errorf("failed to create mirror directory %s: %w", mirrorDir, err)
+		}
+	}
+
+	mirrored := map[string]bool{}
+
+	for _, file := range files {
+		rel, _ := filepath.Rel(dirs.GetRootDir(), file.Path)
+
+		if len(file.Providers) == 0 {
+			log.Printf("⏭️  %s declares no required providers", rel)
+			continue
+		}
+
+		for _, provider := range file.Providers {
+			log.Printf("🔍 %s: %s %s", rel, provider.Source, provider.Version)
+		}
+
+		if dryRun {
+			continue
+		}
+
+		config := renderRequiredProviders(file.Providers)
+
+		lock, err := readLockFile(file.LockFile)
+		if err != nil {
+			return err
+		}
+
+		// Configurations with the same requirements and lock file resolve to the same providers.
+		key := config + "\x00" + lock
+		if mirrored[key] {
+			continue
+		}
+		mirrored[key] = true
+
+		if err := mirrorProviders(terraformBinary, mirrorDir, config, lock, targetPlatforms); err != nil {
+			return fmt.Errorf("failed to mirror providers of %s: %w", rel, err)
+		}
+	}
+
+	if !dryRun {
+		log.Printf("✅ Provider mirror populated at: %s", mirrorDir)
+	}
+
+	return nil
+}
+
+// renderRequiredProviders renders a minimal configuration declaring only the given providers.
+func renderRequiredProviders(providers []repo.ProviderRequirement) string {
+	var b strings.Builder
+
+	b.WriteString("terraform {\n  required_providers {\n")
+
+	for _, provider := range providers {
+		fmt.Fprintf(&b, "    %s = {\n      source = %q\n", provider.Name, provider.Source)
+
+		if provider.Version != "" {
+			fmt.Fprintf(&b, "      version = %q\n", provider.Version)
+		}
+
+		b.WriteString("    }\n")
+	}
+
+	b.WriteString("  }\n}\n")
+
+	return b.String()
+}
+
+// readLockFile returns the content of a dependency lock file, or an empty string when there is none.
+func readLockFile(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read lock file %s: %w", path, err)
+	}
+
+	return string(content), nil
+}
+
+// mirrorProviders runs `terraform providers mirror` on a scratch configuration holding config and,
+// when present, the lock file, so the mirrored versions are the ones the lock file selects.
+// Local module calls are left out of the scratch configuration because each module's own
+// versions.tf is mirrored separately.
+func mirrorProviders(terraformBinary, mirrorDir, config, lock string, targetPlatforms []string) error {
+	workDir, err := os.MkdirTemp("", "tf-provider-mirror-")
+	if err != nil {
+		return fmt.Errorf("failed to create scratch configuration: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	if err := os.WriteFile(filepath.Join(workDir, "versions.tf"), []byte(config), 0o600); err != nil {
+		return fmt.Errorf("failed to write scratch configuration: %w", err)
+	}
+
+	if lock != "" {
+		if err := os.WriteFile(filepath.Join(workDir, ".terraform.lock.hcl"), []byte(lock), 0o600); err != nil {
+			return fmt.Errorf("failed to write scratch lock file: %w", err)
+		}
+	}
+
+	args := []string{"providers", "mirror"}
+	for _, platform := range targetPlatforms {
+		args = append(args, "-platform="+platform)
+	}
+	args = append(args, mirrorDir)
+
+	cmd := exec.Command(terraformBinary, args...) //nolint:gosec // The binary and arguments come from the command line of a developer tool.
+	cmd.Dir = workDir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
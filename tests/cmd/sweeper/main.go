@@ -0,0 +1,113 @@
+// Command sweeper deletes the resources left behind by integration runs that panicked or timed out
+// before destroying them. It only touches resources carrying the run ID tag of pkg/helper that are
+// older than the age threshold or past their expiry tag, and deletes them in dependency order:
+// CodeArtifact repositories, domain policies and domains, then S3 buckets, KMS keys and log groups,
+// then IAM roles, policies and OIDC providers.
+//
+// Usage:
+//
+//	go run ./cmd/sweeper -dry-run [-region us-west-2 -region eu-west-1] [-older-than 3h] [-run-id k3x9]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/harness"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/sweeper"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// defaultOlderThan leaves alone the resources of runs that may still be in progress.
+const defaultOlderThan = 3 * time.Hour
+
+// regions collects the repeatable -region flag.
+type regions []string
+
+func (r *regions) String() string { return strings.Join(*r, ",") }
+
+func (r *regions) Set(value string) error {
+	*r = append(*r, value)
+	return nil
+}
+
+func main() {
+	var targetRegions regions
+
+	flag.Var(&targetRegions, "region", "region to sweep; repeatable (defaults to the regions of the test context)")
+	olderThan := flag.Duration("older-than", defaultOlderThan, "only sweep resources created at least this long ago")
+	runID := flag.String("run-id", "", "only sweep the resources of this test run")
+	dryRun := flag.Bool("dry-run", false, "list the resources that would be swept without deleting them")
+	global := flag.Bool("global", true, "also sweep IAM roles, policies and OIDC providers (once, with the first region)")
+	endpoint := flag.String("endpoint", os.Getenv(helper.AWSEndpointEnvVar), "AWS endpoint URL of a local emulator (defaults to $"+helper.AWSEndpointEnvVar+")")
+	flag.Parse()
+
+	if len(targetRegions) == 0 {
+		cfg, err := harness.LoadConfig()
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+
+		targetRegions = cfg.Regions
+	}
+
+	opts := sweeper.Options{OlderThan: *olderThan, RunID: *runID, DryRun: *dryRun}
+
+	if err := run(targetRegions, opts, *global, *endpoint); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+}
+
+// run sweeps every region, the global IAM resources with the first one.
+func run(targetRegions []string, opts sweeper.Options, global bool, endpoint string) error {
+	ctx := context.Background()
+
+	var (
+		total int
+		errs  []error
+	)
+
+	for i, region := range targetRegions {
+		loadOptions := []func(*config.LoadOptions) error{config.WithRegion(region)}
+		if endpoint != "" {
+			loadOptions = append(loadOptions, config.WithBaseEndpoint(endpoint))
+		}
+
+		cfg, err := config.LoadDefaultConfig(ctx, loadOptions...)
+		if err != nil {
+			return fmt.Errorf("failed to load AWS configuration for region %s: %w", region, err)
+		}
+
+		regionOpts := opts
+		regionOpts.IncludeGlobal = global && i == 0
+
+		log.Printf("🔍 Sweeping %s for resources tagged %s older than %s", region, helper.RunIDTagKey, opts.OlderThan)
+
+		swept, err := sweeper.New(cfg, regionOpts, func(o *s3.Options) { o.UsePathStyle = endpoint != "" }).Run(ctx)
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		total += len(swept)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	if opts.DryRun {
+		log.Printf("✅ Dry run: %d resource(s) would be swept", total)
+	} else {
+		log.Printf("✅ Swept %d resource(s)", total)
+	}
+
+	return nil
+}
@@ -0,0 +1,252 @@
+###################################
+# Target Test Configuration for the Full Stack 🎯
+# ----------------------------------------------------
+#
+# This configuration chains the modules the way they
+# are deployed together:
+# foundation → domain → repository → domain and
+# repository permissions → cross-account role.
+#
+###################################
+
+terraform {
+  required_version = ">= 1.10.0"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+  }
+}
+
+provider "aws" {
+  region = var.aws_region
+}
+
+data "aws_caller_identity" "current" {}
+
+locals {
+  # Root principal of the account running the test; the permissions policies grant it access so
+  # that the cross-account role, which lives in the same account, can use them.
+  account_root = ["arn:aws:iam::${data.aws_caller_identity.current.account_id}:root"]
+}
+
+# Foundation: KMS key, audit log group and backup bucket
+module "foundation" {
+  source = "../../../../../modules/foundation"
+
+  codeartifact_domain_name = var.domain_name
+  kms_key_alias            = var.kms_key_alias
+  log_group_name           = var.log_group_name
+  s3_bucket_name           = var.s3_bucket_name
+  force_destroy_bucket     = true
+  tags                     = var.tags
+}
+
+# Domain encrypted with the foundation key
+module "domain" {
+  source = "../../../../../modules/domain"
+
+  domain_name = var.domain_name
+  kms_key_arn = module.foundation.kms_key_arn
+  tags        = var.tags
+}
+
+# Repository in the domain
+module "repository" {
+  source = "../../../../../modules/repository"
+
+  domain_name     = module.domain.domain_name
+  repository_name = var.repository_name
+  tags            = var.tags
+}
+
+# Domain permissions policy
+module "domain_permissions" {
+  source = "../../../../../modules/domain-permissions"
+
+  domain_name                    = module.domain.domain_name
+  domain_owner                   = module.domain.domain_owner
+  read_principals                = local.account_root
+  list_repo_principals           = local.account_root
+  authorization_token_principals = local.account_root
+}
+
+# Repository permissions policy
+module "repository_permissions" {
+  source = "../../../../../modules/repository-permissions"
+
+  domain_name                    = module.domain.domain_name
+  repository_name                = module.repository.repository_name
+  domain_owner                   = module.repository.repository_domain_owner
+  read_principals                = local.account_root
+  describe_principals            = local.account_root
+  authorization_token_principals = local.account_root
+}
+
+# Cross-account role allowed to use the domain and repository
+module "cross_account" {
+  source = "../../../../../modules/domain-permissions-cross-account"
+
+  role_name                         = var.role_name
+  external_principals_arns_override = local.account_root
+  external_principals               = []
+
+  iam_role_cross_account_policies = [
+    {
+      name = "${var.role_name}-token"
+      policy = jsonencode({
+        Version = "2012-10-17"
+        Statement = [
+          {
+            Effect   = "Allow"
+            Action   = ["codeartifact:GetAuthorizationToken"]
+            Resource = module.domain_permissions.resource_arn
+          },
+          {
+            Effect   = "Allow"
+            Action   = ["sts:GetServiceBearerToken"]
+            Resource = "*"
+            Condition = {
+              StringEquals = { "sts:AWSServiceName" = "codeartifact.amazonaws.com" }
+            }
+          }
+        ]
+      })
+    },
+    {
+      name = "${var.role_name}-read"
+      policy = jsonencode({
+        Version = "2012-10-17"
+        Statement = [
+          {
+            Effect   = "Allow"
+            Action   = ["codeartifact:ReadFromRepository", "codeartifact:DescribeRepository"]
+            Resource = module.repository_permissions.resource_arn
+          }
+        ]
+      })
+    }
+  ]
+
+  tags = var.tags
+}
+
+output "foundation_kms_key_arn" {
+  description = "ARN of the foundation KMS key"
+  value       = module.foundation.kms_key_arn
+}
+
+output "domain_arn" {
+  description = "ARN of the domain"
+  value       = module.domain.domain_arn
+}
+
+output "domain_name" {
+  description = "Name of the domain"
+  value       = module.domain.domain_name
+}
+
+output "domain_owner" {
+  description = "Account that owns the domain"
+  value       = module.domain.domain_owner
+}
+
+output "domain_encryption_key" {
+  description = "KMS key the domain is encrypted with"
+  value       = module.domain.domain_encryption_key
+}
+
+output "repository_arn" {
+  description = "ARN of the repository"
+  value       = module.repository.repository_arn
+}
+
+output "repository_name" {
+  description = "Name of the repository"
+  value       = module.repository.repository_name
+}
+
+output "repository_domain_owner" {
+  description = "Account that owns the domain of the repository"
+  value       = module.repository.repository_domain_owner
+}
+
+output "domain_permissions_resource_arn" {
+  description = "Domain the domain permissions policy is attached to"
+  value       = module.domain_permissions.resource_arn
+}
+
+output "domain_permissions_domain_owner" {
+  description = "Domain owner the domain permissions policy was built for"
+  value       = module.domain_permissions.domain_owner
+}
+
+output "repository_permissions_resource_arn" {
+  description = "Repository the repository permissions policy is attached to"
+  value       = module.repository_permissions.resource_arn
+}
+
+output "cross_account_role_arn" {
+  description = "ARN of the cross-account role"
+  value       = module.cross_account.cross_account_role_arn
+}
+
+output "cross_account_policy_arns" {
+  description = "ARNs of the policies attached to the cross-account role"
+  value       = module.cross_account.policy_arns
+}
+
+variable "aws_region" {
+  type        = string
+  description = "AWS region to deploy the stack in."
+  default     = "us-west-2"
+}
+
+variable "domain_name" {
+  type        = string
+  description = "Name of the CodeArtifact domain."
+  default     = "composition-domain"
+}
+
+variable "repository_name" {
+  type        = string
+  description = "Name of the CodeArtifact repository."
+  default     = "composition-repository"
+}
+
+variable "role_name" {
+  type        = string
+  description = "Name of the cross-account IAM role."
+  default     = "composition-cross-account"
+}
+
+variable "kms_key_alias" {
+  type        = string
+  description = "Alias of the foundation KMS key."
+  default     = "alias/composition-codeartifact"
+}
+
+variable "log_group_name" {
+  type        = string
+  description = "Name of the foundation log group."
+  default     = "/aws/codeartifact/composition"
+}
+
+variable "s3_bucket_name" {
+  type        = string
+  description = "Name of the foundation S3 bucket."
+  default     = "composition-codeartifact-backup"
+}
+
+variable "tags" {
+  type        = map(string)
+  description = "Tags to apply to all resources."
+  default = {
+    environment = "testing"
+    module      = "composition"
+    purpose     = "terratest-validation"
+    managed-by  = "terraform"
+  }
+}
@@ -0,0 +1,83 @@
+//go:build unit && integration
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/harness"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/naming"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/plan"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/waiter"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCompositionOnFullStackTargetWhenApplied verifies that the applied full stack threads the
+// outputs of each module into the modules built on top of it, and that teardown destroys every
+// resource before the resources it depends on.
+func TestCompositionOnFullStackTargetWhenApplied(t *testing.T) {
+	t.Parallel()
+
+	harness.ForEachRegion(t, func(t *testing.T, awsCtx harness.Context) {
+		terraformOptions := helper.NewTerraformOptions(t, helper.TargetSource("composition", "full-stack"),
+			awsCtx.TerraformOption(),
+			helper.WithWorkspaceCopy(),
+			helper.WithVars(map[string]interface{}{
+				"domain_name":     naming.Name(t, naming.CodeArtifactDomain, "composition"),
+				"repository_name": naming.Name(t, naming.CodeArtifactRepository, "composition"),
+				"role_name":       naming.Name(t, naming.IAMRole, "composition"),
+				"kms_key_alias":   naming.Name(t, naming.KMSAlias, "alias/composition"),
+				"s3_bucket_name":  naming.Name(t, naming.S3Bucket, "composition"),
+				"log_group_name":  naming.Name(t, naming.LogGroup, "/aws/codeartifact/composition"),
+			}),
+			helper.WithNoColor(),
+		)
+
+		// Destroy the stack when the test completes, checking that dependents go first, and wait
+		// until AWS reports every resource deleted
+		defer waiter.DestroyInOrderAndWait(t, terraformOptions, awsCtx.Region)
+
+		t.Logf("🔍 Terraform Target Directory: %s", terraformOptions.TerraformDir)
+
+		helper.Init(t, terraformOptions)
+		terraform.Apply(t, terraformOptions)
+
+		// Verify a second plan is empty, so the configuration has no perpetual diff
+		plan.RequireIdempotent(t, terraformOptions)
+
+		output := func(name string) string {
+			value := terraform.Output(t, terraformOptions, name)
+			require.NotEmpty(t, value, "Output %s should be set", name)
+
+			return value
+		}
+
+		// foundation → domain
+		require.Equal(t, output("foundation_kms_key_arn"), output("domain_encryption_key"),
+			"The domain should be encrypted with the foundation KMS key")
+
+		// domain → repository
+		require.Equal(t, output("domain_owner"), output("repository_domain_owner"),
+			"The repository should belong to the domain of the stack")
+		require.Equal(t, awsCtx.AccountID(t, terraformOptions), output("domain_owner"), "The domain should be owned by the test account")
+
+		// domain → domain permissions
+		require.Equal(t, output("domain_arn"), output("domain_permissions_resource_arn"),
+			"The domain permissions policy should be attached to the domain of the stack")
+		require.Equal(t, output("domain_owner"), output("domain_permissions_domain_owner"),
+			"The domain permissions policy should be built for the domain owner")
+
+		// repository → repository permissions
+		require.Equal(t, output("repository_arn"), output("repository_permissions_resource_arn"),
+			"The repository permissions policy should be attached to the repository of the stack")
+
+		// permissions → cross-account role
+		require.Contains(t, output("cross_account_role_arn"), ":role/", "The cross-account role should be created")
+		require.Len(t, terraform.OutputList(t, terraformOptions, "cross_account_policy_arns"), 2,
+			"Both cross-account policies should be attached to the role")
+
+		t.Log("✅ Full stack outputs are threaded between modules")
+	})
+}
@@ -0,0 +1,67 @@
+//go:build unit && readonly
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/harness"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/plan"
+)
+
+// TestCompositionOnFullStackTargetWhenPlanned verifies that the full stack plans every module and
+// wires the outputs of each module into the inputs of the modules built on top of it.
+func TestCompositionOnFullStackTargetWhenPlanned(t *testing.T) {
+	t.Parallel()
+
+	terraformOptions := helper.NewTerraformOptions(t, helper.TargetSource("composition", "full-stack"),
+		harness.Load(t).TerraformOption(),
+		helper.WithWorkspaceCopy(),
+		helper.WithVars(map[string]interface{}{
+			"domain_name":     "composition-plan",
+			"repository_name": "composition-plan-repository",
+		}),
+		helper.WithNoColor(),
+	)
+
+	t.Logf("🔍 Terraform Target Directory: %s", terraformOptions.TerraformDir)
+
+	tfPlan := plan.InitAndPlan(t, terraformOptions)
+
+	for _, address := range []string{
+		"module.foundation.aws_kms_key.this[0]",
+		"module.domain.aws_codeartifact_domain.this[0]",
+		"module.repository.aws_codeartifact_repository.this[0]",
+		"module.domain_permissions.aws_codeartifact_domain_permissions_policy.this[0]",
+		"module.repository_permissions.aws_codeartifact_repository_permissions_policy.this[0]",
+		`module.cross_account.aws_iam_role.cross_account_role["enabled"]`,
+	} {
+		plan.RequireResourceAction(t, tfPlan, address, plan.ActionCreate)
+	}
+
+	wiring := []struct {
+		call, input, reference string
+	}{
+		{"domain", "kms_key_arn", "module.foundation.kms_key_arn"},
+		{"repository", "domain_name", "module.domain.domain_name"},
+		{"domain_permissions", "domain_name", "module.domain.domain_name"},
+		{"domain_permissions", "domain_owner", "module.domain.domain_owner"},
+		{"repository_permissions", "domain_name", "module.domain.domain_name"},
+		{"repository_permissions", "repository_name", "module.repository.repository_name"},
+		{"repository_permissions", "domain_owner", "module.repository.repository_domain_owner"},
+		{"cross_account", "iam_role_cross_account_policies", "module.domain_permissions.resource_arn"},
+		{"cross_account", "iam_role_cross_account_policies", "module.repository_permissions.resource_arn"},
+	}
+
+	for _, w := range wiring {
+		plan.RequireModuleInputReference(t, tfPlan, w.call, w.input, w.reference)
+	}
+
+	// The domain name is known at plan time, so it reaches the modules built on the domain
+	plan.RequireAfterAttribute(t, tfPlan, "module.repository.aws_codeartifact_repository.this[0]", "domain", "composition-plan")
+	plan.RequireAfterAttribute(t, tfPlan, "module.domain_permissions.aws_codeartifact_domain_permissions_policy.this[0]", "domain", "composition-plan")
+	plan.RequireAfterAttribute(t, tfPlan, "module.repository_permissions.aws_codeartifact_repository_permissions_policy.this[0]", "repository", "composition-plan-repository")
+
+	t.Logf("✅ Full stack planned with %d module inputs wired", len(wiring))
+}
@@ -22,16 +22,16 @@ func TestInitializationOnBasicExampleWhenModuleEnabled(t *testing.T) {
 	// Enable parallel test execution
 	t.Parallel()
 
-	// Use helper function to setup terraform options with isolated provider cache
+	// Use helper function to setup terraform options with isolated provider cache and workspace
 	terraformOptions := helper.SetupTerraformOptions(t, "default/basic", map[string]interface{}{
 		"is_enabled": true,
-	})
+	}, helper.WithWorkspaceCopy())
 
 	// Log the test context
 	t.Logf("🔍 Testing example at directory: %s", terraformOptions.TerraformDir)
 
 	// Execution phase - Initialize the module
-	initOutput, err := terraform.InitE(t, terraformOptions)
+	initOutput, err := helper.InitE(t, terraformOptions)
 	require.NoError(t, err, "Terraform init failed")
 	t.Log("✅ Terraform Init Output:\n", initOutput)
 
@@ -47,14 +47,14 @@ func TestValidationOnBasicExampleWhenTerraformInitialized(t *testing.T) {
 	// Enable parallel test execution
 	t.Parallel()
 
-	// Use helper function to setup terraform options with isolated provider cache
-	terraformOptions := helper.SetupTerraformOptions(t, "default/basic", nil)
+	// Use helper function to setup terraform options with isolated provider cache and workspace
+	terraformOptions := helper.SetupTerraformOptions(t, "default/basic", nil, helper.WithWorkspaceCopy())
 
 	// Log the test context
 	t.Logf("🔍 Testing example at directory: %s", terraformOptions.TerraformDir)
 
 	// Execution phase - Initialize the module
-	initOutput, err := terraform.InitE(t, terraformOptions)
+	initOutput, err := helper.InitE(t, terraformOptions)
 	require.NoError(t, err, "Terraform init failed")
 	t.Log("✅ Terraform Init Output:\n", initOutput)
 
@@ -75,16 +75,16 @@ func TestPlanningOnBasicExampleWhenModuleDisabled(t *testing.T) {
 	// Enable parallel test execution
 	t.Parallel()
 
-	// Use helper function to setup terraform options with isolated provider cache
+	// Use helper function to setup terraform options with isolated provider cache and workspace
 	terraformOptions := helper.SetupTerraformOptions(t, "default/basic", map[string]interface{}{
 		"is_enabled": false,
-	})
+	}, helper.WithWorkspaceCopy())
 
 	// Log the test context
 	t.Logf("🔍 Testing disabled module at directory: %s", terraformOptions.TerraformDir)
 
 	// Execution phase - Initialize the module
-	initOutput, err := terraform.InitE(t, terraformOptions)
+	initOutput, err := helper.InitE(t, terraformOptions)
 	require.NoError(t, err, "Terraform init failed")
 	t.Log("✅ Terraform Init Output:\n", initOutput)
 
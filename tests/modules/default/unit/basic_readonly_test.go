@@ -24,7 +24,7 @@ func TestInitializationOnModuleWhenUpgradeEnabled(t *testing.T) {
 
 	t.Logf("🔍 Terraform Module Directory: %s", terraformOptions.TerraformDir)
 
-	initOutput, err := terraform.InitE(t, terraformOptions)
+	initOutput, err := helper.InitE(t, terraformOptions)
 	require.NoError(t, err, "Terraform init failed")
 	t.Log("✅ Terraform Init Output:\n", initOutput)
 }
@@ -43,7 +43,7 @@ func TestValidationOnModuleWhenBasicConfiguration(t *testing.T) {
 	t.Logf("🔍 Terraform Module Directory: %s", terraformOptions.TerraformDir)
 
 	// Initialize with detailed error handling
-	initOutput, err := terraform.InitE(t, terraformOptions)
+	initOutput, err := helper.InitE(t, terraformOptions)
 	require.NoError(t, err, "Terraform init failed")
 	t.Log("✅ Terraform Init Output:\n", initOutput)
 
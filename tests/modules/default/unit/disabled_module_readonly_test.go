@@ -6,7 +6,7 @@ import (
 	"testing"
 
 	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
-	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/plan"
 	"github.com/stretchr/testify/require"
 )
 
@@ -20,18 +20,10 @@ func TestPlanningOnTargetWhenModuleDisabled(t *testing.T) {
 
 	t.Logf("🔍 Terraform Target Directory: %s", terraformOptions.TerraformDir)
 
-	// Initialize with detailed error handling
-	initOutput, err := terraform.InitE(t, terraformOptions)
-	require.NoError(t, err, "Terraform init failed")
-	t.Log("✅ Terraform Init Output:\n", initOutput)
-
-	// Plan to show what would be created in the disabled_module target
-	planOutput, err := terraform.PlanE(t, terraformOptions)
-	require.NoError(t, err, "Terraform plan failed")
-	t.Log("📝 Terraform Plan Output:\n", planOutput)
+	tfPlan := plan.InitAndPlan(t, terraformOptions)
 
 	// Verify plan does not contain the random_string resource
-	require.NotContains(t, planOutput, "random_string.random_text", "Plan should not include random_string resource when module is disabled")
+	plan.RequireResourceTypeNotPlanned(t, tfPlan, "random_string")
 }
 
 // TestOutputsOnTargetWhenModuleDisabled verifies that the module outputs
@@ -44,16 +36,10 @@ func TestOutputsOnTargetWhenModuleDisabled(t *testing.T) {
 
 	t.Logf("🔍 Terraform Target Directory: %s", terraformOptions.TerraformDir)
 
-	// Initialize with detailed error handling
-	initOutput, err := terraform.InitE(t, terraformOptions)
-	require.NoError(t, err, "Terraform init failed")
-	t.Log("✅ Terraform Init Output:\n", initOutput)
-
-	// Plan to show what would be created in the disabled_module target
-	planOutput, err := terraform.PlanE(t, terraformOptions)
-	require.NoError(t, err, "Terraform plan failed")
-	t.Log("📝 Terraform Plan Output:\n", planOutput)
+	tfPlan := plan.InitAndPlan(t, terraformOptions)
 
 	// Verify plan contains the expected outputs
-	require.Contains(t, planOutput, "is_enabled = false", "Plan should show is_enabled output as false")
+	isEnabled, ok := tfPlan.OutputChange("is_enabled")
+	require.True(t, ok, "Output is_enabled should be planned")
+	require.Equal(t, false, isEnabled.After, "Output is_enabled should be false when module is disabled")
 }
@@ -5,53 +5,55 @@ package unit
 import (
 	"testing"
 
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/contract"
 	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
-	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/plan"
 	"github.com/stretchr/testify/require"
 )
 
-// TestOutputsOnBasicTarget verifies that all expected outputs are present
-// in the plan for the basic target configuration.
+// TestOutputsOnBasicTarget verifies that the default module declares and plans exactly
+// the outputs of its contract, enabled and disabled.
 func TestOutputsOnBasicTarget(t *testing.T) {
 	t.Parallel()
 
-	// Use helper function to setup terraform options with isolated provider cache
-	terraformOptions := helper.SetupTargetTerraformOptions(t, "default", "basic", map[string]interface{}{
-		"is_enabled": true,
+	contract.Run(t, contract.Default, map[string]interface{}{
+		"tags": map[string]string{"purpose": "terratest-validation"},
 	})
-
-	t.Logf("🔍 Terraform Target Directory: %s", terraformOptions.TerraformDir)
-
-	// Initialize with detailed error handling
-	initOutput, err := terraform.InitE(t, terraformOptions)
-	require.NoError(t, err, "Terraform init failed")
-	t.Log("✅ Terraform Init Output:\n", initOutput)
-
-	// Plan to show what would be created in the basic target
-	planOutput, err := terraform.PlanE(t, terraformOptions)
-	require.NoError(t, err, "Terraform plan failed")
-	t.Log("📝 Terraform Plan Output:\n", planOutput)
 }
 
-// TestOutputValuesOnBasicTarget verifies that the output values are as expected
-// in the plan for the basic target configuration.
+// TestOutputValuesOnBasicTarget verifies that the basic target passes the module outputs
+// through with the planned values.
 func TestOutputValuesOnBasicTarget(t *testing.T) {
 	t.Parallel()
 
+	tags := map[string]string{
+		"environment": "testing",
+		"purpose":     "terratest-validation",
+	}
+
 	// Use helper function to setup terraform options with isolated provider cache
 	terraformOptions := helper.SetupTargetTerraformOptions(t, "default", "basic", map[string]interface{}{
 		"is_enabled": true,
+		"tags":       tags,
 	})
 
 	t.Logf("🔍 Terraform Target Directory: %s", terraformOptions.TerraformDir)
 
-	// Initialize with detailed error handling
-	initOutput, err := terraform.InitE(t, terraformOptions)
-	require.NoError(t, err, "Terraform init failed")
-	t.Log("✅ Terraform Init Output:\n", initOutput)
+	tfPlan := plan.InitAndPlan(t, terraformOptions)
+
+	isEnabled, ok := tfPlan.OutputChange("module_is_enabled")
+	require.True(t, ok, "Output module_is_enabled should be planned")
+	require.Equal(t, true, isEnabled.After, "Output module_is_enabled should be true")
+
+	moduleTags, ok := tfPlan.OutputChange("module_tags")
+	require.True(t, ok, "Output module_tags should be planned")
+
+	planned, ok := moduleTags.After.(map[string]interface{})
+	require.True(t, ok, "Output module_tags should be a known map, got %v", moduleTags.After)
+
+	for key, value := range tags {
+		require.Equal(t, value, planned[key], "Output module_tags should carry tag %q", key)
+	}
 
-	// Plan to show what would be created in the basic target
-	planOutput, err := terraform.PlanE(t, terraformOptions)
-	require.NoError(t, err, "Terraform plan failed")
-	t.Log("📝 Terraform Plan Output:\n", planOutput)
+	t.Logf("✅ Basic target outputs: is_enabled=%v, %d tags", isEnabled.After, len(planned))
 }
@@ -0,0 +1,67 @@
+//go:build readonly && examples
+
+package examples
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/harness"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/iampolicy"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/plan"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/recipe"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/repo"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPlanningOnDomainPermissionsCrossAccountExampleWhenAllRecipesAreUsed verifies the Terraform plan
+// generation for every domain-permissions-cross-account example with every fixture recipe discovered
+// in its fixtures/ directory.
+func TestPlanningOnDomainPermissionsCrossAccountExampleWhenAllRecipesAreUsed(t *testing.T) {
+	t.Parallel()
+
+	recipe.Run(t, "domain-permissions-cross-account", recipe.Config{
+		Golden: true,
+		Assert: assertDomainPermissionsCrossAccountRecipe,
+	})
+}
+
+// assertDomainPermissionsCrossAccountRecipe checks the plan of the basic example according to the
+// fixture used. Recipes of the other examples only need to plan successfully.
+func assertDomainPermissionsCrossAccountRecipe(t *testing.T, example repo.Example, fixture repo.Fixture, tfPlan *plan.Plan) {
+	if example.Path != "domain-permissions-across-account/basic" {
+		return
+	}
+
+	domainAddress := "aws_codeartifact_domain.example[0]"
+	roleAddress := `module.this.aws_iam_role.cross_account_role["enabled"]`
+
+	if fixture.Name == "disabled" {
+		plan.RequireResourceNotPlanned(t, tfPlan, roleAddress)
+		plan.RequireNoManagedResources(t, tfPlan)
+
+		return
+	}
+
+	plan.RequireResourceAction(t, tfPlan, domainAddress, plan.ActionCreate)
+	plan.RequireResourceAction(t, tfPlan, roleAddress, plan.ActionCreate)
+
+	// The example attaches a token policy and a read policy, named after the role
+	for _, suffix := range []string{"token", "read"} {
+		name := "dpca-basic-role-example-" + suffix
+		plan.RequireResourceAction(t, tfPlan, fmt.Sprintf("module.this.aws_iam_policy.policies[%q]", name), plan.ActionCreate)
+		plan.RequireResourceAction(t, tfPlan, fmt.Sprintf("module.this.aws_iam_role_policy_attachment.attachment[%q]", name), plan.ActionCreate)
+	}
+
+	if fixture.Name == "default" {
+		// The default fixture trusts the roles of its external_principals
+		partition := harness.Load(t).Partition
+		trustPolicy := iampolicy.RequirePlannedDocument(t, tfPlan, roleAddress, "assume_role_policy")
+
+		require.Len(t, trustPolicy.Statements, 1, "Trust policy should have a single statement")
+		require.ElementsMatch(t, []string{
+			fmt.Sprintf("arn:%s:iam::111122223333:role/dpca-basic-role-example", partition),
+			fmt.Sprintf("arn:%s:iam::444455556666:role/dpca-basic-role-example", partition),
+		}, trustPolicy.Statements[0].Principals["AWS"], "Trust policy should name the external principals of the fixture")
+	}
+}
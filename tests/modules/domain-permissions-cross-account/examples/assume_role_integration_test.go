@@ -0,0 +1,106 @@
+//go:build integration && examples
+
+package examples
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/harness"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/naming"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/plan"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/waiter"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/codeartifact"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAssumeRoleOnDomainPermissionsCrossAccountExampleWhenSecondPrincipalIsTrusted applies the basic
+// example trusting the second configured principal, assumes the created role as that principal and
+// checks that the session can get a CodeArtifact authorization token for the example's domain.
+// It is skipped unless TF_TEST_AWS_ASSUMER_PROFILE names the profile of the second principal.
+func TestAssumeRoleOnDomainPermissionsCrossAccountExampleWhenSecondPrincipalIsTrusted(t *testing.T) {
+	t.Parallel()
+
+	harness.ForEachRegion(t, func(t *testing.T, awsCtx harness.Context) {
+		// Setup terraform options with isolated provider cache and workspace for the fixture in the test region
+		terraformOptions := helper.NewTerraformOptions(t, helper.ExampleSource("domain-permissions-across-account/basic"),
+			helper.WithWorkspaceCopy(),
+			awsCtx.TerraformOption(),
+			helper.WithVarFiles("fixtures/default.tfvars"),
+			helper.WithVars(map[string]interface{}{
+				"domain_name": naming.Name(t, naming.CodeArtifactDomain, "dpca-assume"),
+				"role_name":   naming.Name(t, naming.IAMRole, "dpca-assume"),
+			}),
+		)
+
+		// The second principal talks to the same endpoint as Terraform, and is the only one trusted
+		assumer := awsCtx.Assumer(t, terraformOptions)
+		terraformOptions.Vars["external_principals_arns_override"] = []string{assumer.PrincipalARN}
+
+		// Destroy resources when the test completes and wait until AWS reports them deleted
+		defer waiter.DestroyAndWait(t, terraformOptions, awsCtx.Region)
+
+		t.Logf("🔍 Terraform Example Directory: %s", terraformOptions.TerraformDir)
+		t.Logf("📝 Using fixture: fixtures/default.tfvars, trusting %s", assumer.PrincipalARN)
+
+		// Initialize and apply Terraform
+		helper.Init(t, terraformOptions)
+		terraform.Apply(t, terraformOptions)
+
+		// Verify a second plan is empty, so the configuration has no perpetual diff
+		plan.RequireIdempotent(t, terraformOptions)
+
+		roleARN := terraform.Output(t, terraformOptions, "cross_account_role_arn")
+		roleName := terraform.Output(t, terraformOptions, "cross_account_role_name")
+		domainName := terraform.Output(t, terraformOptions, "example_domain_name")
+		domainOwner := terraform.Output(t, terraformOptions, "example_domain_owner")
+
+		ctx := context.Background()
+		stsClient := sts.NewFromConfig(assumer.Config)
+
+		// A new trust policy takes a few seconds to propagate through IAM, so AssumeRole is retried
+		var session *sts.AssumeRoleOutput
+		_, err := retry.DoWithRetryE(t, fmt.Sprintf("Assume role %s", roleARN), 12, 10*time.Second, func() (string, error) {
+			out, err := stsClient.AssumeRole(ctx, &sts.AssumeRoleInput{
+				RoleArn:         aws.String(roleARN),
+				RoleSessionName: aws.String("terratest-cross-account"),
+			})
+			if err != nil {
+				return "", err
+			}
+
+			session = out
+			return aws.ToString(out.AssumedRoleUser.Arn), nil
+		})
+		require.NoError(t, err, "The second principal should be able to assume the cross-account role")
+		require.Contains(t, aws.ToString(session.AssumedRoleUser.Arn), ":assumed-role/"+roleName+"/",
+			"The session should belong to the cross-account role")
+
+		t.Logf("✅ Assumed %s as %s", roleARN, assumer.PrincipalARN)
+
+		// Use the session credentials, in the same region and endpoint as the second principal
+		sessionConfig := assumer.Config.Copy()
+		sessionConfig.Credentials = aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(
+			aws.ToString(session.Credentials.AccessKeyId),
+			aws.ToString(session.Credentials.SecretAccessKey),
+			aws.ToString(session.Credentials.SessionToken),
+		))
+
+		token, err := codeartifact.NewFromConfig(sessionConfig).GetAuthorizationToken(ctx, &codeartifact.GetAuthorizationTokenInput{
+			Domain:      aws.String(domainName),
+			DomainOwner: aws.String(domainOwner),
+		})
+		require.NoError(t, err, "The role session should be able to get an authorization token for domain %s", domainName)
+		require.NotEmpty(t, aws.ToString(token.AuthorizationToken), "The authorization token should not be empty")
+
+		t.Logf("✅ Role session got an authorization token for domain %s", domainName)
+	})
+}
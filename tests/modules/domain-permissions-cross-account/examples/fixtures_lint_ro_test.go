@@ -0,0 +1,18 @@
+//go:build readonly && examples
+
+package examples
+
+import (
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/lint"
+)
+
+// TestValidationOnDomainPermissionsCrossAccountExampleFixturesWhenComparedToVariables verifies that every fixture of
+// every domain-permissions-cross-account example only assigns declared variables, with values of the declared type, and sets
+// every required variable.
+func TestValidationOnDomainPermissionsCrossAccountExampleFixturesWhenComparedToVariables(t *testing.T) {
+	t.Parallel()
+
+	lint.RequireValidFixtures(t, "domain-permissions-cross-account")
+}
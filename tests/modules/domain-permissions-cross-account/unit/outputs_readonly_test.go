@@ -0,0 +1,22 @@
+//go:build unit && readonly
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/contract"
+)
+
+// TestOutputContractOnDomainPermissionsCrossAccountModuleWhenEnabledOrDisabled verifies that the domain-permissions-cross-account module declares and
+// plans exactly the outputs of its contract, enabled and disabled.
+func TestOutputContractOnDomainPermissionsCrossAccountModuleWhenEnabledOrDisabled(t *testing.T) {
+	t.Parallel()
+
+	contract.Run(t, contract.DomainPermissionsCrossAccount, map[string]interface{}{
+		"role_name": "contract-cross-account-role",
+		"external_principals": []map[string]interface{}{
+			{"account_id": "123456789012", "role_name": "contract-ci"},
+		},
+	})
+}
@@ -0,0 +1,104 @@
+//go:build unit && readonly
+
+package unit
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/harness"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/iampolicy"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/plan"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAttachmentsOnDomainPermissionsCrossAccountModuleWhenPoliciesAreSet verifies that every entry of
+// iam_role_cross_account_policies becomes a managed policy with its document, path and description,
+// attached to the cross-account role.
+func TestAttachmentsOnDomainPermissionsCrossAccountModuleWhenPoliciesAreSet(t *testing.T) {
+	t.Parallel()
+
+	const (
+		roleName    = "attachments-cross-account-role"
+		tokenPolicy = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"codeartifact:GetAuthorizationToken","Resource":"*"}]}`
+		readPolicy  = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["codeartifact:ReadFromRepository","codeartifact:DescribeRepository"],"Resource":"*"}]}`
+	)
+
+	awsCtx := harness.Load(t)
+
+	tfPlan := planCrossAccountModule(t, awsCtx, map[string]interface{}{
+		"role_name": roleName,
+		"external_principals": []map[string]interface{}{
+			{"account_id": "111122223333", "role_name": "codeartifact-ci"},
+		},
+		"iam_role_cross_account_policies": []map[string]interface{}{
+			{"name": "attachments-token", "policy": tokenPolicy},
+			{"name": "attachments-read", "policy": readPolicy, "path": "/codeartifact/", "description": "Read packages"},
+		},
+	})
+
+	policies := []struct {
+		name        string
+		document    string
+		path        string
+		description string
+	}{
+		// Entries without a path or description get the optional attribute defaults
+		{"attachments-token", tokenPolicy, "/", "Managed by Terraform"},
+		{"attachments-read", readPolicy, "/codeartifact/", "Read packages"},
+	}
+
+	require.Len(t, tfPlan.ResourceChangesByType("aws_iam_policy"), len(policies), "One policy should be planned per entry")
+	require.Len(t, tfPlan.ResourceChangesByType("aws_iam_role_policy_attachment"), len(policies), "One attachment should be planned per entry")
+
+	for _, p := range policies {
+		policyAddress := fmt.Sprintf("aws_iam_policy.policies[%q]", p.name)
+		attachmentAddress := fmt.Sprintf("aws_iam_role_policy_attachment.attachment[%q]", p.name)
+
+		plan.RequireResourceAction(t, tfPlan, policyAddress, plan.ActionCreate)
+		plan.RequireAfterAttribute(t, tfPlan, policyAddress, "name", p.name)
+		plan.RequireAfterAttribute(t, tfPlan, policyAddress, "path", p.path)
+		plan.RequireAfterAttribute(t, tfPlan, policyAddress, "description", p.description)
+
+		expected, err := iampolicy.Parse(p.document)
+		require.NoError(t, err, "Policy %s should be a valid policy document", p.name)
+		iampolicy.RequireEquivalent(t, expected, iampolicy.RequirePlannedDocument(t, tfPlan, policyAddress, "policy"))
+
+		// The attachment joins the policy to the role created by the module
+		plan.RequireResourceAction(t, tfPlan, attachmentAddress, plan.ActionCreate)
+		plan.RequireAfterAttribute(t, tfPlan, attachmentAddress, "role", roleName)
+
+		rc := plan.RequireResourcePlanned(t, tfPlan, attachmentAddress)
+		require.True(t, rc.Change.IsAfterUnknown("policy_arn"), "Attachment %s should use the ARN of the policy created with it", p.name)
+
+		t.Logf("✅ Policy %s is created and attached to %s", p.name, roleName)
+	}
+}
+
+// TestSessionDurationOnDomainPermissionsCrossAccountModuleWhenAtTheBounds verifies that the bounds
+// of max_session_duration, one hour and twelve hours, are accepted and planned on the role. Values
+// outside of them are covered by the validation matrix.
+func TestSessionDurationOnDomainPermissionsCrossAccountModuleWhenAtTheBounds(t *testing.T) {
+	t.Parallel()
+
+	awsCtx := harness.Load(t)
+
+	for _, duration := range []int{3600, 43200} {
+		duration := duration
+
+		t.Run(fmt.Sprint(duration), func(t *testing.T) {
+			t.Parallel()
+
+			tfPlan := planCrossAccountModule(t, awsCtx, map[string]interface{}{
+				"role_name":            fmt.Sprintf("session-%d-cross-account-role", duration),
+				"max_session_duration": duration,
+				"external_principals": []map[string]interface{}{
+					{"account_id": "111122223333", "role_name": "codeartifact-ci"},
+				},
+			})
+
+			plan.RequireAfterAttribute(t, tfPlan, roleAddress, "max_session_duration", duration)
+			t.Logf("✅ max_session_duration %d is planned on the role", duration)
+		})
+	}
+}
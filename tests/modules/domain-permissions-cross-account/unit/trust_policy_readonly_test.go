@@ -0,0 +1,122 @@
+//go:build unit && readonly
+
+package unit
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/harness"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/iampolicy"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/plan"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/repo"
+	"github.com/stretchr/testify/require"
+)
+
+// roleAddress is the cross-account role created when the module is enabled.
+const roleAddress = `aws_iam_role.cross_account_role["enabled"]`
+
+// planCrossAccountModule plans the module with the given variables in an isolated workspace.
+func planCrossAccountModule(t *testing.T, awsCtx harness.Context, vars map[string]interface{}) *plan.Plan {
+	t.Helper()
+
+	dirs, err := repo.NewTFSourcesDir()
+	require.NoError(t, err, "Failed to get Terraform sources directory")
+
+	terraformOptions := helper.NewTerraformOptions(t, helper.ModuleSource(dirs.GetModulesDir("domain-permissions-cross-account")),
+		awsCtx.TerraformOption(),
+		helper.WithWorkspaceCopy(),
+		helper.WithNoColor(),
+		helper.WithVars(vars),
+	)
+
+	t.Logf("🔍 Terraform Module Directory: %s", terraformOptions.TerraformDir)
+
+	return plan.InitAndPlan(t, terraformOptions)
+}
+
+// TestTrustPolicyOnDomainPermissionsCrossAccountModuleWhenExternalPrincipalsAreSet verifies that the
+// planned trust policy lets exactly the roles built from external_principals assume the role.
+func TestTrustPolicyOnDomainPermissionsCrossAccountModuleWhenExternalPrincipalsAreSet(t *testing.T) {
+	t.Parallel()
+
+	awsCtx := harness.Load(t)
+	ci := fmt.Sprintf("arn:%s:iam::111122223333:role/codeartifact-ci", awsCtx.Partition)
+	readers := fmt.Sprintf("arn:%s:iam::444455556666:role/codeartifact-readers", awsCtx.Partition)
+
+	tfPlan := planCrossAccountModule(t, awsCtx, map[string]interface{}{
+		"role_name": "trust-cross-account-role",
+		"external_principals": []map[string]interface{}{
+			{"account_id": "111122223333", "role_name": "codeartifact-ci"},
+			{"account_id": "444455556666", "role_name": "codeartifact-readers"},
+		},
+	})
+
+	trustPolicy := iampolicy.RequirePlannedDocument(t, tfPlan, roleAddress, "assume_role_policy")
+
+	expected, err := iampolicy.Parse(fmt.Sprintf(`{
+		"Version": "2012-10-17",
+		"Statement": [{"Effect": "Allow", "Action": "sts:AssumeRole", "Principal": {"AWS": [%q, %q]}}]
+	}`, ci, readers))
+	require.NoError(t, err, "Expected trust policy should be valid")
+
+	iampolicy.RequireEquivalent(t, expected, trustPolicy)
+
+	cases := []struct {
+		name      string
+		principal string
+		action    string
+		expected  iampolicy.Decision
+	}{
+		{"ci role assumes the role", ci, "sts:AssumeRole", iampolicy.Allow},
+		{"readers role assumes the role", readers, "sts:AssumeRole", iampolicy.Allow},
+		{"ci role cannot tag a session", ci, "sts:TagSession", iampolicy.ImplicitDeny},
+		{"other role of a trusted account", fmt.Sprintf("arn:%s:iam::111122223333:role/other", awsCtx.Partition), "sts:AssumeRole", iampolicy.ImplicitDeny},
+		{"same role name in another account", fmt.Sprintf("arn:%s:iam::777788889999:role/codeartifact-ci", awsCtx.Partition), "sts:AssumeRole", iampolicy.ImplicitDeny},
+	}
+
+	for _, c := range cases {
+		iampolicy.RequireDecision(t, trustPolicy, iampolicy.Request{Principal: c.principal, Action: c.action}, c.expected)
+		t.Logf("✅ %s: %s", c.name, c.expected)
+	}
+}
+
+// TestTrustPolicyOnDomainPermissionsCrossAccountModuleWhenArnsOverrideIsSet verifies that
+// external_principals_arns_override replaces the principals built from external_principals.
+func TestTrustPolicyOnDomainPermissionsCrossAccountModuleWhenArnsOverrideIsSet(t *testing.T) {
+	t.Parallel()
+
+	awsCtx := harness.Load(t)
+	override := fmt.Sprintf("arn:%s:iam::444455556666:role/platform/codeartifact-override", awsCtx.Partition)
+	ignored := fmt.Sprintf("arn:%s:iam::111122223333:role/codeartifact-ci", awsCtx.Partition)
+
+	tfPlan := planCrossAccountModule(t, awsCtx, map[string]interface{}{
+		"role_name":                         "override-cross-account-role",
+		"external_principals_arns_override": []string{override},
+		"external_principals": []map[string]interface{}{
+			{"account_id": "111122223333", "role_name": "codeartifact-ci"},
+		},
+	})
+
+	trustPolicy := iampolicy.RequirePlannedDocument(t, tfPlan, roleAddress, "assume_role_policy")
+	require.Len(t, trustPolicy.Statements, 1, "Trust policy should have a single statement")
+
+	statement := trustPolicy.Statements[0]
+	require.Equal(t, map[string][]string{"AWS": {override}}, statement.Principals, "Only the override ARNs should be trusted")
+
+	iampolicy.RequireDecision(t, trustPolicy, iampolicy.Request{Principal: override, Action: "sts:AssumeRole"}, iampolicy.Allow)
+	iampolicy.RequireDecision(t, trustPolicy, iampolicy.Request{Principal: ignored, Action: "sts:AssumeRole"}, iampolicy.ImplicitDeny)
+
+	// Both inputs are reported as set, even though only the override is used
+	flags, ok := tfPlan.Output("feature_flags")
+	require.True(t, ok, "Plan should include the feature_flags output")
+	require.Equal(t, map[string]interface{}{
+		"is_enabled": true,
+		"are_iam_role_cross_account_policies_set": false,
+		"are_external_principals_set":             true,
+		"are_external_principals_override_set":    true,
+	}, flags.Value, "Feature flags should report both trust inputs")
+
+	t.Log("✅ external_principals_arns_override takes precedence over external_principals")
+}
@@ -0,0 +1,50 @@
+//go:build unit && readonly
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/validation"
+)
+
+// TestValidationOnDomainPermissionsCrossAccountModuleWhenVariablesAreInvalid verifies that every
+// validation block of the domain-permissions-cross-account module rejects invalid values with its
+// exact error message.
+func TestValidationOnDomainPermissionsCrossAccountModuleWhenVariablesAreInvalid(t *testing.T) {
+	t.Parallel()
+
+	const maxSessionDurationMessage = "The maximum session duration must be between 3600 and 43200 seconds."
+
+	validation.Run(t, validation.Matrix{
+		Module: "domain-permissions-cross-account",
+		Vars: map[string]interface{}{
+			"role_name": "validation-cross-account-role",
+			"external_principals": []map[string]interface{}{
+				{"account_id": "123456789012", "role_name": "validation-ci"},
+			},
+		},
+		Cases: []validation.Case{
+			{
+				Name:     "malformed-json",
+				Variable: "iam_role_cross_account_policies",
+				Vars: map[string]interface{}{"iam_role_cross_account_policies": []map[string]interface{}{
+					{"name": "validation-policy", "policy": `{"Version": "2012-10-17"`},
+				}},
+				ErrorMessage: "The 'policy' attribute for each item in 'policies' must be a valid JSON string.",
+			},
+			{
+				Name:         "below-minimum",
+				Variable:     "max_session_duration",
+				Vars:         map[string]interface{}{"max_session_duration": 3599},
+				ErrorMessage: maxSessionDurationMessage,
+			},
+			{
+				Name:         "above-maximum",
+				Variable:     "max_session_duration",
+				Vars:         map[string]interface{}{"max_session_duration": 43201},
+				ErrorMessage: maxSessionDurationMessage,
+			},
+		},
+	})
+}
@@ -3,115 +3,103 @@
 package examples
 
 import (
-	"path/filepath"
-	"strings"
 	"testing"
 
-	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
-	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/iampolicy"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/plan"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/recipe"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/repo"
 	"github.com/stretchr/testify/require"
 )
 
-// getFixtureName extracts the name from a fixture file path without the extension
-func getFixtureName(fixturePath string) string {
-	base := filepath.Base(fixturePath)
-	return strings.TrimSuffix(base, filepath.Ext(base))
+// plannedPolicy returns the policy generated by the module. The module reads its
+// aws_iam_policy_document after the example's domain exists, so the statements are taken from the
+// deferred data source read when the planned policy_document is not yet known.
+func plannedPolicy(t *testing.T, tfPlan *plan.Plan) *iampolicy.Document {
+	t.Helper()
+
+	if doc, err := iampolicy.FromPlan(tfPlan, "module.this[0].aws_codeartifact_domain_permissions_policy.this[0]", "policy_document"); err == nil {
+		return doc
+	}
+
+	doc, err := iampolicy.FromPolicyDocumentData(tfPlan, "module.this[0].data.aws_iam_policy_document.combined[0]")
+	require.NoError(t, err, "Deferred policy document read should be planned")
+
+	return doc
 }
 
 // TestPlanningOnDomainPermissionsExampleBasicWhenAllRecipesAreUsed verifies the Terraform plan generation
-// for the domain-permissions basic example with all available fixture recipes. It checks if the plan
-// includes the expected resources based on the fixture used.
+// for every domain-permissions example with every fixture recipe discovered in its fixtures/ directory.
+// It checks if the plan of the basic example includes the expected resources based on the fixture used.
 func TestPlanningOnDomainPermissionsExampleBasicWhenAllRecipesAreUsed(t *testing.T) {
 	t.Parallel()
 
-	// Define fixtures to test based on the files found in examples/domain-permissions/basic/fixtures/
-	fixtures := []string{
-		"default.tfvars",
-		"disabled.tfvars",
-		"no-policy.tfvars",
+	recipe.Run(t, "domain-permissions", recipe.Config{
+		Golden: true,
+		Assert: assertDomainPermissionsRecipe,
+	})
+}
+
+// assertDomainPermissionsRecipe checks the plan of the basic example according to the fixture used.
+// Recipes of the other examples only need to plan successfully.
+func assertDomainPermissionsRecipe(t *testing.T, example repo.Example, fixture repo.Fixture, tfPlan *plan.Plan) {
+	if example.Path != "domain-permissions/basic" {
+		return
 	}
 
-	exampleDir := "domain-permissions/basic" // Relative path to the example
-
-	for _, fixture := range fixtures {
-		// Using local variable to ensure proper capture in closure
-		fixture := fixture
-		fixtureName := getFixtureName(fixture) // Extract name without extension
-
-		// Create a subtest for each fixture
-		t.Run(fixtureName, func(t *testing.T) {
-			t.Parallel()
-
-			// Construct the path to the fixture file
-			fixturePath := filepath.Join("fixtures", fixture)
-
-			// Use helper function to setup terraform options with isolated provider cache
-			// Pass nil for vars, as they are provided via VarFiles
-			terraformOptions := helper.SetupTerraformOptions(t, exampleDir, nil)
-
-			// Add var file to the options
-			terraformOptions.VarFiles = []string{fixturePath}
-
-			t.Logf("🔍 Terraform Example Directory: %s", terraformOptions.TerraformDir)
-			t.Logf("📝 Using fixture: %s", fixturePath)
-
-			// Initialize Terraform
-			initOutput, err := terraform.InitE(t, terraformOptions)
-			if err != nil {
-				t.Logf("⚠️ Terraform init failed for fixture %s: %v", fixture, err)
-				t.Logf("Skipping further tests for this fixture")
-				return
-			}
-			t.Logf("✅ Terraform Init Output (Fixture: %s):\n%s", fixture, initOutput)
-
-			// Generate Terraform plan
-			planOutput, err := terraform.PlanE(t, terraformOptions)
-			if err != nil {
-				t.Logf("⚠️ Terraform plan failed for fixture %s: %v", fixture, err)
-				t.Logf("Skipping assertions for this fixture")
-				return
-			}
-			t.Logf("📝 Terraform Plan Output (Fixture: %s):\n%s", fixture, planOutput)
-
-			// --- Assertions based on fixture ---
-			policyResourceIdentifier := "module.this[0].aws_codeartifact_domain_permissions_policy.this"
-			domainResourceIdentifier := "aws_codeartifact_domain.this[0]" // Domain created by the example itself
-
-			if fixture == "disabled.tfvars" {
-				// When disabled, neither the example's domain nor the module's policy should be planned
-				require.NotContains(t, planOutput, domainResourceIdentifier, "Plan should NOT contain domain resource when example is disabled")
-				require.NotContains(t, planOutput, policyResourceIdentifier, "Plan should NOT contain policy resource when example is disabled")
-			} else {
-				// For all enabled fixtures, the example's domain resource should be planned
-				require.Contains(t, planOutput, domainResourceIdentifier, "Plan should contain domain resource for enabled fixture %s", fixture)
-
-				// The 'default' and 'no-policy' fixtures rely on the example's main.tf logic,
-				// which forces the current account root into 'read_principals'.
-				// This means 'local.has_baseline_inputs' in the module becomes true,
-				// and the policy resource *is* created by the module.
-				require.Contains(t, planOutput, policyResourceIdentifier, "Plan should contain policy resource for enabled fixture %s", fixture)
-
-				// Specific checks for policy content (optional, can be brittle)
-				if fixture == "default.tfvars" || fixture == "no-policy.tfvars" {
-					// Check for the default statements added by the module/example logic
-					require.Contains(t, planOutput, "DefaultOwnerReadDomainPolicy", "Plan should contain default owner SID for fixture %s", fixture)
-					require.Contains(t, planOutput, "BaselineReadDomainPolicy", "Plan should contain baseline read SID for fixture %s (due to example logic)", fixture)
-				}
-
-				// Removing checks for fixtures not in our test list
-				// if fixture == "cross_account.tfvars" {
-				//     // Assuming cross_account.tfvars uses custom_policy_statements,
-				//     // we can check for a potential SID if known, or just the resource presence (already done above).
-				//     // require.Contains(t, planOutput, "AllowCrossAccountAccess", "Plan should contain cross-account SID for fixture %s", fixture) // Example SID
-				// }
-
-				// if fixture == "custom-domain-owner.tfvars" {
-				//     // Check if the domain_owner attribute is being set in the policy resource block
-				//     // This is a basic check; a more robust check might parse the plan JSON.
-				//     require.Contains(t, planOutput, "domain_owner", "Plan output for policy resource should mention domain_owner for fixture %s", fixture)
-				//     // Note: We don't know the exact owner ID from the fixture here, so just check for the attribute name.
-				// }
-			}
+	policyResourceAddress := "module.this[0].aws_codeartifact_domain_permissions_policy.this[0]"
+	domainResourceAddress := "aws_codeartifact_domain.this[0]" // Domain created by the example itself
+
+	if fixture.Name == "disabled" {
+		// When disabled, neither the example's domain nor the module's policy should be planned
+		plan.RequireResourceNotPlanned(t, tfPlan, domainResourceAddress)
+		plan.RequireResourceNotPlanned(t, tfPlan, policyResourceAddress)
+
+		return
+	}
+
+	// For all enabled fixtures, the example's domain resource should be planned
+	plan.RequireResourceAction(t, tfPlan, domainResourceAddress, plan.ActionCreate)
+
+	// The example's main.tf logic forces the current account root into 'read_principals'
+	// when none are provided, so the policy resource *is* created by the module.
+	plan.RequireResourceAction(t, tfPlan, policyResourceAddress, plan.ActionCreate)
+
+	policy := plannedPolicy(t, tfPlan)
+	iampolicy.RequireStatementMatches(t, policy, "DefaultOwnerReadDomainPolicy", iampolicy.Expected{
+		Effect: "Allow",
+		Actions: []string{
+			"codeartifact:GetDomainPermissionsPolicy",
+			"codeartifact:ListRepositoriesInDomain",
+			"codeartifact:GetAuthorizationToken",
+			"codeartifact:DescribeDomain",
+			"codeartifact:CreateRepository",
+		},
+	})
+
+	switch fixture.Name {
+	case "default", "no-policy":
+		// Check for the default statements added by the module/example logic
+		iampolicy.RequireStatementMatches(t, policy, "BaselineReadDomainPolicy", iampolicy.Expected{
+			Effect:    "Allow",
+			Actions:   []string{"codeartifact:DescribeDomain", "codeartifact:GetDomainPermissionsPolicy"},
+			Resources: []string{"*"},
+		})
+	case "cross_account":
+		// The custom statement is merged into the generated policy
+		iampolicy.RequireStatementMatches(t, policy, "AllowCrossAccountDomainAccess", iampolicy.Expected{
+			Effect:     "Allow",
+			Principals: map[string][]string{"AWS": {"arn:aws:iam::ACCOUNT_ID_TO_GRANT_ACCESS:root"}},
+			Actions: []string{
+				"codeartifact:GetDomainPermissionsPolicy",
+				"codeartifact:ListRepositoriesInDomain",
+				"codeartifact:GetAuthorizationToken",
+				"sts:GetServiceBearerToken",
+			},
+			Resources: []string{"*"},
 		})
+	case "custom-domain-owner":
+		// The policy is attached to the domain of the configured owner
+		plan.RequireAfterAttribute(t, tfPlan, policyResourceAddress, "domain_owner", "123456789012")
 	}
 }
@@ -0,0 +1,93 @@
+//go:build unit && readonly
+
+package unit
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/harness"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/iampolicy"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/plan"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/repo"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAuthorizationOnDomainPermissionsModuleWhenBaselinePrincipalsAreSet plans the module with
+// principals for every baseline statement and a custom deny, then evaluates the planned policy
+// offline to check who can call which domain action.
+func TestAuthorizationOnDomainPermissionsModuleWhenBaselinePrincipalsAreSet(t *testing.T) {
+	t.Parallel()
+
+	const (
+		domainName  = "authorization-domain"
+		domainOwner = "111122223333"
+		reader      = "arn:aws:iam::444455556666:role/codeartifact-reader"
+		lister      = "arn:aws:iam::444455556666:role/codeartifact-lister"
+		publisher   = "arn:aws:iam::444455556666:role/codeartifact-ci"
+		blocked     = "arn:aws:iam::444455556666:role/blocked-ci"
+		outsider    = "arn:aws:iam::777788889999:role/codeartifact-ci"
+	)
+
+	awsCtx := harness.Load(t)
+	domainARN := fmt.Sprintf("arn:%s:codeartifact:%s:%s:domain/%s", awsCtx.Partition, awsCtx.Region, domainOwner, domainName)
+
+	dirs, err := repo.NewTFSourcesDir()
+	require.NoError(t, err, "Failed to get Terraform sources directory")
+
+	terraformOptions := helper.NewTerraformOptions(t, helper.ModuleSource(dirs.GetModulesDir("domain-permissions")),
+		awsCtx.TerraformOption(),
+		helper.WithWorkspaceCopy(),
+		helper.WithNoColor(),
+		helper.WithVars(map[string]interface{}{
+			"domain_name":                    domainName,
+			"domain_owner":                   domainOwner,
+			"read_principals":                []string{reader},
+			"list_repo_principals":           []string{lister},
+			"authorization_token_principals": []string{publisher, blocked},
+			"custom_policy_statements": []map[string]interface{}{{
+				"Sid":       "DenyBlockedTokens",
+				"Effect":    "Deny",
+				"Principal": map[string]interface{}{"Type": "AWS", "Identifiers": []string{"*"}},
+				"Action":    []string{"codeartifact:GetAuthorizationToken"},
+				"Resource":  []string{"*"},
+				"Condition": map[string]interface{}{
+					"Test":     "StringLike",
+					"Variable": iampolicy.PrincipalARNKey,
+					"Values":   []string{"arn:aws:iam::444455556666:role/blocked-*"},
+				},
+			}},
+		}),
+	)
+
+	t.Logf("🔍 Terraform Module Directory: %s", terraformOptions.TerraformDir)
+
+	tfPlan := plan.InitAndPlan(t, terraformOptions)
+	policy := iampolicy.RequirePlannedDocument(t, tfPlan, "aws_codeartifact_domain_permissions_policy.this[0]", "policy_document")
+
+	cases := []struct {
+		name      string
+		principal string
+		action    string
+		expected  iampolicy.Decision
+	}{
+		{"reader reads the domain policy", reader, "codeartifact:GetDomainPermissionsPolicy", iampolicy.Allow},
+		{"reader describes the domain", reader, "codeartifact:DescribeDomain", iampolicy.Allow},
+		{"reader session describes the domain", "arn:aws:sts::444455556666:assumed-role/codeartifact-reader/session", "codeartifact:DescribeDomain", iampolicy.Allow},
+		{"reader gets no token", reader, "codeartifact:GetAuthorizationToken", iampolicy.ImplicitDeny},
+		{"reader lists no repositories", reader, "codeartifact:ListRepositoriesInDomain", iampolicy.ImplicitDeny},
+		{"lister lists repositories", lister, "codeartifact:ListRepositoriesInDomain", iampolicy.Allow},
+		{"lister describes nothing", lister, "codeartifact:DescribeDomain", iampolicy.ImplicitDeny},
+		{"publisher gets a token", publisher, "codeartifact:GetAuthorizationToken", iampolicy.Allow},
+		{"publisher gets a bearer token", publisher, "sts:GetServiceBearerToken", iampolicy.Allow},
+		{"publisher reads no domain policy", publisher, "codeartifact:GetDomainPermissionsPolicy", iampolicy.ImplicitDeny},
+		{"blocked principal is denied a token", blocked, "codeartifact:GetAuthorizationToken", iampolicy.ExplicitDeny},
+		{"outsider gets no token", outsider, "codeartifact:GetAuthorizationToken", iampolicy.ImplicitDeny},
+	}
+
+	for _, c := range cases {
+		iampolicy.RequireDecision(t, policy, iampolicy.Request{Principal: c.principal, Action: c.action, Resource: domainARN}, c.expected)
+		t.Logf("✅ %s: %s", c.name, c.expected)
+	}
+}
@@ -0,0 +1,19 @@
+//go:build unit && readonly
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/contract"
+)
+
+// TestOutputContractOnDomainPermissionsModuleWhenEnabledOrDisabled verifies that the domain-permissions module declares and
+// plans exactly the outputs of its contract, enabled and disabled.
+func TestOutputContractOnDomainPermissionsModuleWhenEnabledOrDisabled(t *testing.T) {
+	t.Parallel()
+
+	contract.Run(t, contract.DomainPermissions, map[string]interface{}{
+		"domain_name": "contract-domain",
+	})
+}
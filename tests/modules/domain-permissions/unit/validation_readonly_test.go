@@ -0,0 +1,72 @@
+//go:build unit && readonly
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/validation"
+)
+
+// TestValidationOnDomainPermissionsModuleWhenVariablesAreInvalid verifies that every validation block
+// of the domain-permissions module rejects invalid values with its exact error message.
+func TestValidationOnDomainPermissionsModuleWhenVariablesAreInvalid(t *testing.T) {
+	t.Parallel()
+
+	const statementMessage = "Each custom_policy_statement must be an object containing at least 'Effect' ('Allow' or 'Deny'), 'Action' (list of strings), and 'Resource' (list of strings)."
+
+	validation.Run(t, validation.Matrix{
+		Module: "domain-permissions",
+		Vars: map[string]interface{}{
+			"domain_name": "validation-domain",
+		},
+		Cases: []validation.Case{
+			{
+				Name:         "trailing-hyphen",
+				Variable:     "domain_name",
+				Vars:         map[string]interface{}{"domain_name": "validation-domain-"},
+				ErrorMessage: "The domain name must be between 2-63 characters, contain only lowercase letters, numbers, and hyphens, cannot start with a hyphen, and cannot end with a hyphen.",
+			},
+			{
+				Name:         "malformed-json",
+				Variable:     "policy_document_override",
+				Vars:         map[string]interface{}{"policy_document_override": `{"Version": "2012-10-17"`},
+				ErrorMessage: "The policy_document_override must be a valid JSON string or null.",
+			},
+			{
+				Name:         "short-account-id",
+				Variable:     "read_principals",
+				Vars:         map[string]interface{}{"read_principals": []string{"arn:aws:iam::123:root"}},
+				ErrorMessage: "Each item in read_principals must be a valid IAM principal ARN (account root, user, or role) or '*'.",
+			},
+			{
+				Name:         "not-an-arn",
+				Variable:     "list_repo_principals",
+				Vars:         map[string]interface{}{"list_repo_principals": []string{"123456789012"}},
+				ErrorMessage: "Each item in list_repo_principals must be a valid IAM principal ARN (account root, user, or role) or '*'.",
+			},
+			{
+				Name:         "group-principal",
+				Variable:     "authorization_token_principals",
+				Vars:         map[string]interface{}{"authorization_token_principals": []string{"arn:aws:iam::123456789012:group/developers"}},
+				ErrorMessage: "Each item in authorization_token_principals must be a valid IAM principal ARN (account root, user, or role) or '*'.",
+			},
+			{
+				Name:     "invalid-effect",
+				Variable: "custom_policy_statements",
+				Vars: map[string]interface{}{"custom_policy_statements": []map[string]interface{}{
+					{"Effect": "Permit", "Action": []string{"codeartifact:ReadFromRepository"}, "Resource": []string{"*"}},
+				}},
+				ErrorMessage: statementMessage,
+			},
+			{
+				Name:     "missing-resource",
+				Variable: "custom_policy_statements",
+				Vars: map[string]interface{}{"custom_policy_statements": []map[string]interface{}{
+					{"Effect": "Allow", "Action": []string{"codeartifact:ReadFromRepository"}},
+				}},
+				ErrorMessage: statementMessage,
+			},
+		},
+	})
+}
@@ -5,78 +5,60 @@ package examples
 import (
 	"testing"
 
-	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
-	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/plan"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/recipe"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/repo"
 	"github.com/stretchr/testify/require"
 )
 
 // TestPlanningOnDomainExampleWhenAllRecipesAreUsed verifies the Terraform plan generation
-// for the domain basic example with all available fixture recipes.
+// for every domain example with every fixture recipe discovered in its fixtures/ directory.
 func TestPlanningOnDomainExampleWhenAllRecipesAreUsed(t *testing.T) {
 	t.Parallel()
 
-	// Define fixtures to test
-	fixtures := []string{
-		"default.tfvars",
-		"disabled.tfvars",
-		"no-encryption.tfvars",
-		"with-domain-permissions.tfvars",
-		"custom-domain-owner.tfvars",
-		"combined-features.tfvars",
-	}
-
-	for _, fixture := range fixtures {
-		// Using local variable to ensure proper capture in closure
-		fixture := fixture
-
-		// Create a subtest for each fixture
-		t.Run(fixture, func(t *testing.T) {
-			t.Parallel()
-
-			// Use helper function to setup terraform options with isolated provider cache
-			terraformOptions := helper.SetupTerraformOptions(t, "domain/basic", nil)
-
-			// Add var file to the options
-			terraformOptions.VarFiles = []string{"fixtures/" + fixture}
-
-			t.Logf("🔍 Terraform Example Directory: %s", terraformOptions.TerraformDir)
-			t.Logf("📝 Using fixture: fixtures/%s", fixture)
-
-			initOutput, err := terraform.InitE(t, terraformOptions)
-			require.NoError(t, err, "Terraform init failed")
-			t.Log("✅ Terraform Init Output:\n", initOutput)
-
-			planOutput, err := terraform.PlanE(t, terraformOptions)
-			require.NoError(t, err, "Terraform plan failed")
-			t.Log("📝 Terraform Plan Output:\n", planOutput)
+	recipe.Run(t, "domain", recipe.Config{
+		Golden: true,
+		Assert: assertDomainRecipe,
+	})
+}
 
-			// Verify plan output according to the fixture
-			if fixture == "disabled.tfvars" {
-				require.NotContains(t, planOutput, "aws_codeartifact_domain.this", "Disabled fixture should not create domain")
-			} else {
-				// For enabled fixtures, verify domain resource is planned
-				require.Contains(t, planOutput, "aws_codeartifact_domain.this", "Plan should include CodeArtifact domain resource")
-			}
+// assertDomainRecipe checks the plan of a domain example according to the fixture used.
+// Fixtures without specific expectations only need to plan successfully.
+func assertDomainRecipe(t *testing.T, example repo.Example, fixture repo.Fixture, tfPlan *plan.Plan) {
+	if example.Path != "domain/basic" {
+		return
+	}
 
-			// Add other specific checks based on fixture
-			if fixture == "with-domain-permissions.tfvars" {
-				require.Contains(t, planOutput, "aws_codeartifact_domain_permissions_policy", "Plan should include domain permissions policy")
-			}
+	domainAddress := "module.this.aws_codeartifact_domain.this[0]"
+	policyAddress := "module.this.aws_codeartifact_domain_permissions_policy.this[0]"
+	kmsKeyAddress := "aws_kms_key.this[0]"
 
-			if fixture == "custom-domain-owner.tfvars" {
-				require.Contains(t, planOutput, "domain_owner", "Plan should include custom domain owner")
-			}
+	// Verify plan according to the fixture
+	if fixture.Name == "disabled" {
+		plan.RequireResourceNotPlanned(t, tfPlan, domainAddress)
+		plan.RequireNoManagedResources(t, tfPlan)
 
-			if fixture == "no-encryption.tfvars" {
-				// Instead of looking for "encryption_key" not being present, we should check
-				// for the absence of a custom KMS key resource in the plan
-				require.NotContains(t, planOutput, "aws_kms_key.this", "Plan should not include custom KMS key with no-encryption fixture")
-			}
+		return
+	}
 
-			if fixture == "combined-features.tfvars" {
-				require.Contains(t, planOutput, "aws_codeartifact_domain_permissions_policy", "Plan should include domain permissions policy")
-				require.Contains(t, planOutput, "encryption_key", "Plan should include encryption key")
-			}
-		})
+	// For enabled fixtures, verify domain resource is planned
+	plan.RequireResourceAction(t, tfPlan, domainAddress, plan.ActionCreate)
+	plan.RequireAfterAttribute(t, tfPlan, domainAddress, "domain", "example-domain")
+
+	// Add other specific checks based on fixture
+	switch fixture.Name {
+	case "with-domain-permissions":
+		plan.RequireResourceAction(t, tfPlan, policyAddress, plan.ActionCreate)
+	case "custom-domain-owner":
+		// The domain owner is reflected in the endpoint computed by the module
+		endpoint, ok := tfPlan.Output("domain_endpoint")
+		require.True(t, ok, "Plan should include the domain_endpoint output")
+		require.Contains(t, endpoint.Value, "example-domain-123456789012", "Domain endpoint should use the custom domain owner")
+	case "no-encryption":
+		// No custom KMS key is created; the domain falls back to the AWS managed key
+		plan.RequireResourceNotPlanned(t, tfPlan, kmsKeyAddress)
+	case "combined-features":
+		plan.RequireResourceAction(t, tfPlan, policyAddress, plan.ActionCreate)
+		plan.RequireResourceAction(t, tfPlan, kmsKeyAddress, plan.ActionCreate)
 	}
 }
@@ -4,9 +4,12 @@ package examples
 
 import (
 	"testing"
-	"time"
 
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/harness"
 	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/naming"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/plan"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/waiter"
 	"github.com/gruntwork-io/terratest/modules/terraform"
 	"github.com/stretchr/testify/require"
 )
@@ -16,41 +19,44 @@ import (
 func TestDeploymentOnDomainExampleWhenDefaultFixture(t *testing.T) {
 	t.Parallel()
 
-	// Use helper function to setup terraform options with isolated provider cache
-	terraformOptions := helper.SetupTerraformOptions(t, "domain/basic", nil)
-
-	// Add var file to the options for the default fixture
-	terraformOptions.VarFiles = []string{"fixtures/default.tfvars"}
-
-	// Cleanup resources when the test completes
-	defer func() {
-		terraform.Destroy(t, terraformOptions)
-		helper.WaitForResourceDeletion(t, 7*time.Second)
-	}()
-
-	t.Logf("🔍 Terraform Example Directory: %s", terraformOptions.TerraformDir)
-	t.Logf("📝 Using fixture: fixtures/default.tfvars")
-
-	// Initialize Terraform
-	initOutput, err := terraform.InitE(t, terraformOptions)
-	require.NoError(t, err, "Terraform init failed")
-	t.Log("✅ Terraform Init Output:\n", initOutput)
-
-	// Plan Terraform configuration
-	planOutput, err := terraform.PlanE(t, terraformOptions)
-	require.NoError(t, err, "Terraform plan failed")
-	t.Log("📝 Terraform Plan Output:\n", planOutput)
-
-	// Verify that resources are planned when module is enabled with default fixture
-	require.Contains(t, planOutput, "aws_codeartifact_domain.this",
-		"CodeArtifact domain resource should be planned when module is enabled")
-
-	// Apply Terraform configuration
-	applyOutput, err := terraform.ApplyE(t, terraformOptions)
-	require.NoError(t, err, "Terraform apply failed")
-	t.Log("✅ Terraform Apply Output:\n", applyOutput)
-
-	// Verify the is_enabled output is true
-	isEnabledOutput := terraform.Output(t, terraformOptions, "is_enabled")
-	require.Equal(t, "true", isEnabledOutput, "The is_enabled output should be true when the module is enabled")
+	harness.ForEachRegion(t, func(t *testing.T, awsCtx harness.Context) {
+		// Setup terraform options with isolated provider cache and workspace for the fixture in the test region
+		terraformOptions := helper.NewTerraformOptions(t, helper.ExampleSource("domain/basic"),
+			helper.WithWorkspaceCopy(),
+			awsCtx.TerraformOption(),
+			helper.WithVarFiles("fixtures/default.tfvars"),
+			helper.WithVars(map[string]interface{}{
+				"domain_name": naming.Name(t, naming.CodeArtifactDomain, "example-domain"),
+			}),
+		)
+
+		// Destroy resources when the test completes and wait until AWS reports them deleted
+		defer waiter.DestroyAndWait(t, terraformOptions, awsCtx.Region)
+
+		t.Logf("🔍 Terraform Example Directory: %s", terraformOptions.TerraformDir)
+		t.Logf("📝 Using fixture: fixtures/default.tfvars")
+
+		// Initialize and plan Terraform
+		tfPlan := plan.InitAndPlan(t, terraformOptions)
+		t.Logf("📝 Planned resources: %v", tfPlan.Addresses())
+
+		// Verify that the domain is created when module is enabled with default fixture
+		plan.RequireResourceAction(t, tfPlan, "module.this.aws_codeartifact_domain.this[0]", plan.ActionCreate)
+
+		// Apply Terraform configuration
+		applyOutput, err := terraform.ApplyE(t, terraformOptions)
+		require.NoError(t, err, "Terraform apply failed")
+		t.Log("✅ Terraform Apply Output:\n", applyOutput)
+
+		// Verify a second plan is empty, so the configuration has no perpetual diff
+		plan.RequireIdempotent(t, terraformOptions)
+
+		// Verify the is_enabled output is true
+		isEnabledOutput := terraform.Output(t, terraformOptions, "is_enabled")
+		require.Equal(t, "true", isEnabledOutput, "The is_enabled output should be true when the module is enabled")
+
+		// Verify the domain is owned by the account the test runs in
+		domainOwnerOutput := terraform.Output(t, terraformOptions, "domain_owner")
+		require.Equal(t, awsCtx.AccountID(t, terraformOptions), domainOwnerOutput, "The domain should be owned by the test account")
+	})
 }
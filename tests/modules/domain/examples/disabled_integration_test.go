@@ -4,9 +4,11 @@ package examples
 
 import (
 	"testing"
-	"time"
 
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/harness"
 	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/plan"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/waiter"
 	"github.com/gruntwork-io/terratest/modules/terraform"
 	"github.com/stretchr/testify/require"
 )
@@ -16,41 +18,36 @@ import (
 func TestDeploymentOnDomainExampleWhenDisabledFixture(t *testing.T) {
 	t.Parallel()
 
-	// Use helper function to setup terraform options with isolated provider cache
-	terraformOptions := helper.SetupTerraformOptions(t, "domain/basic", nil)
+	harness.ForEachRegion(t, func(t *testing.T, awsCtx harness.Context) {
+		// Setup terraform options with isolated provider cache and workspace for the fixture in the test region
+		terraformOptions := helper.NewTerraformOptions(t, helper.ExampleSource("domain/basic"),
+			helper.WithWorkspaceCopy(),
+			awsCtx.TerraformOption(),
+			helper.WithVarFiles("fixtures/disabled.tfvars"),
+		)
 
-	// Add var file to the options for the disabled fixture
-	terraformOptions.VarFiles = []string{"fixtures/disabled.tfvars"}
+		// Destroy resources when the test completes and wait until AWS reports them deleted
+		defer waiter.DestroyAndWait(t, terraformOptions, awsCtx.Region)
 
-	// Cleanup resources when the test completes
-	defer func() {
-		terraform.Destroy(t, terraformOptions)
-		helper.WaitForResourceDeletion(t, 2*time.Second)
-	}()
+		t.Logf("🔍 Terraform Example Directory: %s", terraformOptions.TerraformDir)
+		t.Logf("📝 Using fixture: fixtures/disabled.tfvars")
 
-	t.Logf("🔍 Terraform Example Directory: %s", terraformOptions.TerraformDir)
-	t.Logf("📝 Using fixture: fixtures/disabled.tfvars")
+		// Initialize and plan Terraform
+		tfPlan := plan.InitAndPlan(t, terraformOptions)
 
-	// Initialize Terraform
-	initOutput, err := terraform.InitE(t, terraformOptions)
-	require.NoError(t, err, "Terraform init failed")
-	t.Log("✅ Terraform Init Output:\n", initOutput)
+		// Verify no resources are planned when module is disabled
+		plan.RequireNoManagedResources(t, tfPlan)
 
-	// Plan Terraform configuration
-	planOutput, err := terraform.PlanE(t, terraformOptions)
-	require.NoError(t, err, "Terraform plan failed")
-	t.Log("📝 Terraform Plan Output:\n", planOutput)
+		// Apply Terraform configuration (which should create no resources)
+		applyOutput, err := terraform.ApplyE(t, terraformOptions)
+		require.NoError(t, err, "Terraform apply failed")
+		t.Log("✅ Terraform Apply Output:\n", applyOutput)
 
-	// Verify no resources are planned when module is disabled
-	require.NotContains(t, planOutput, "aws_codeartifact_domain.this",
-		"No CodeArtifact domain resource should be planned when module is disabled")
+		// Verify a second plan is empty, so the configuration has no perpetual diff
+		plan.RequireIdempotent(t, terraformOptions)
 
-	// Apply Terraform configuration (which should create no resources)
-	applyOutput, err := terraform.ApplyE(t, terraformOptions)
-	require.NoError(t, err, "Terraform apply failed")
-	t.Log("✅ Terraform Apply Output:\n", applyOutput)
-
-	// Verify the is_enabled output is false
-	isEnabledOutput := terraform.Output(t, terraformOptions, "is_enabled")
-	require.Equal(t, "false", isEnabledOutput, "The is_enabled output should be false when the module is disabled")
+		// Verify the is_enabled output is false
+		isEnabledOutput := terraform.Output(t, terraformOptions, "is_enabled")
+		require.Equal(t, "false", isEnabledOutput, "The is_enabled output should be false when the module is disabled")
+	})
 }
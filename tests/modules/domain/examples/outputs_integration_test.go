@@ -0,0 +1,31 @@
+//go:build integration && examples
+
+package examples
+
+import (
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/contract"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/harness"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/naming"
+)
+
+// TestOutputContractOnDomainModuleWhenApplied verifies that `terraform output -json` honours the
+// output contract of the domain module once it is applied, enabled and disabled.
+func TestOutputContractOnDomainModuleWhenApplied(t *testing.T) {
+	t.Parallel()
+
+	harness.ForEachRegion(t, func(t *testing.T, awsCtx harness.Context) {
+		for _, mode := range []contract.Mode{contract.ModeEnabled, contract.ModeDisabled} {
+			mode := mode
+
+			t.Run(string(mode), func(t *testing.T) {
+				t.Parallel()
+
+				contract.RunApplied(t, contract.Domain, awsCtx, map[string]interface{}{
+					"domain_name": naming.Name(t, naming.CodeArtifactDomain, "contract-domain"),
+				}, mode)
+			})
+		}
+	})
+}
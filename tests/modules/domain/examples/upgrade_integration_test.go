@@ -0,0 +1,28 @@
+//go:build integration && examples && upgrade
+
+package examples
+
+import (
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/harness"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/naming"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/upgrade"
+)
+
+// TestUpgradeOnDomainExampleWhenPreviousReleaseIsApplied verifies that the working tree plans no
+// destroy or replacement of the domain applied by the last release of the module.
+func TestUpgradeOnDomainExampleWhenPreviousReleaseIsApplied(t *testing.T) {
+	t.Parallel()
+
+	harness.ForEachRegion(t, func(t *testing.T, awsCtx harness.Context) {
+		upgrade.Run(t, upgrade.Config{
+			Module:   "domain",
+			Example:  "domain/basic",
+			VarFiles: []string{"fixtures/default.tfvars"},
+			Vars: map[string]interface{}{
+				"domain_name": naming.Name(t, naming.CodeArtifactDomain, "upgrade-domain"),
+			},
+		}, awsCtx)
+	})
+}
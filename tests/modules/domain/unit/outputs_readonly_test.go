@@ -0,0 +1,19 @@
+//go:build unit && readonly
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/contract"
+)
+
+// TestOutputContractOnDomainModuleWhenEnabledOrDisabled verifies that the domain module declares and
+// plans exactly the outputs of its contract, enabled and disabled.
+func TestOutputContractOnDomainModuleWhenEnabledOrDisabled(t *testing.T) {
+	t.Parallel()
+
+	contract.Run(t, contract.Domain, map[string]interface{}{
+		"domain_name": "contract-domain",
+	})
+}
@@ -0,0 +1,44 @@
+//go:build unit && readonly
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/validation"
+)
+
+// TestValidationOnDomainModuleWhenVariablesAreInvalid verifies that every validation block of the
+// domain module rejects invalid values with its exact error message.
+func TestValidationOnDomainModuleWhenVariablesAreInvalid(t *testing.T) {
+	t.Parallel()
+
+	const domainNameMessage = "The domain name must be between 2-50 characters, contain only lowercase letters, numbers, and hyphens, cannot start with a hyphen, and cannot end with a hyphen."
+
+	validation.Run(t, validation.Matrix{
+		Module: "domain",
+		Vars: map[string]interface{}{
+			"domain_name": "validation-domain",
+		},
+		Cases: []validation.Case{
+			{
+				Name:         "uppercase",
+				Variable:     "domain_name",
+				Vars:         map[string]interface{}{"domain_name": "Validation-Domain"},
+				ErrorMessage: domainNameMessage,
+			},
+			{
+				Name:         "leading-hyphen",
+				Variable:     "domain_name",
+				Vars:         map[string]interface{}{"domain_name": "-validation-domain"},
+				ErrorMessage: domainNameMessage,
+			},
+			{
+				Name:         "too-long",
+				Variable:     "domain_name",
+				Vars:         map[string]interface{}{"domain_name": "validation-domain-name-that-is-longer-than-fifty-chars"},
+				ErrorMessage: domainNameMessage,
+			},
+		},
+	})
+}
@@ -0,0 +1,45 @@
+//go:build readonly && examples
+
+package examples
+
+import (
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/plan"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/recipe"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/repo"
+)
+
+// TestPlanningOnFoundationExamplesWhenAllRecipesAreUsed verifies the Terraform plan generation
+// for every foundation example with every fixture recipe discovered in its fixtures/ directory.
+func TestPlanningOnFoundationExamplesWhenAllRecipesAreUsed(t *testing.T) {
+	t.Parallel()
+
+	recipe.Run(t, "foundation", recipe.Config{
+		Skip: map[string]string{
+			"foundation/basic/oidc-existing":         "looks up an OIDC provider that must already exist in the account",
+			"foundation/advanced-oidc/oidc-existing": "looks up an OIDC provider that must already exist in the account",
+		},
+		Assert: assertFoundationRecipe,
+		Golden: true,
+	})
+}
+
+// assertFoundationRecipe checks the plan of a foundation example according to the fixture used.
+// Fixtures without specific expectations only need to plan successfully.
+func assertFoundationRecipe(t *testing.T, example repo.Example, fixture repo.Fixture, tfPlan *plan.Plan) {
+	if example.Path != "foundation/basic" {
+		return
+	}
+
+	switch fixture.Name {
+	case "disabled":
+		plan.RequireNoManagedResources(t, tfPlan)
+	case "oidc_github":
+		plan.RequireResourceAction(t, tfPlan, "module.this.aws_iam_openid_connect_provider.oidc[0]", plan.ActionCreate)
+		plan.RequireResourceAction(t, tfPlan, `module.this.aws_iam_role.oidc["github-oidc-foundation-example-role"]`, plan.ActionCreate)
+	case "oidc_gitlab":
+		plan.RequireResourceAction(t, tfPlan, "module.this.aws_iam_openid_connect_provider.oidc[0]", plan.ActionCreate)
+		plan.RequireResourceAction(t, tfPlan, `module.this.aws_iam_role.oidc["gitlab-oidc-foundation-example-role"]`, plan.ActionCreate)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/plan"
 )
 
 // TestInitializationOnExamplesBasicWhenAllFeaturesEnabled verifies that the basic example
@@ -16,12 +17,12 @@ import (
 func TestInitializationOnExamplesBasicWhenAllFeaturesEnabled(t *testing.T) {
 	t.Parallel()
 
-	// Use helper function to setup terraform options with isolated provider cache
-	terraformOptions := helper.SetupTerraformOptions(t, "foundation/basic", nil)
+	// Use helper function to setup terraform options with isolated provider cache and workspace
+	terraformOptions := helper.SetupTerraformOptions(t, "foundation/basic", nil, helper.WithWorkspaceCopy())
 
 	t.Logf("🔍 Terraform Example Directory: %s", terraformOptions.TerraformDir)
 
-	initOutput, err := terraform.InitE(t, terraformOptions)
+	initOutput, err := helper.InitE(t, terraformOptions)
 	require.NoError(t, err, "Terraform init failed")
 	t.Log("✅ Terraform Init Output:\n", initOutput)
 }
@@ -31,10 +32,10 @@ func TestInitializationOnExamplesBasicWhenAllFeaturesEnabled(t *testing.T) {
 func TestValidationOnExamplesBasicWhenAllFeaturesEnabled(t *testing.T) {
 	t.Parallel()
 
-	// Use helper function to setup terraform options with isolated provider cache
-	terraformOptions := helper.SetupTerraformOptions(t, "foundation/basic", nil)
+	// Use helper function to setup terraform options with isolated provider cache and workspace
+	terraformOptions := helper.SetupTerraformOptions(t, "foundation/basic", nil, helper.WithWorkspaceCopy())
 
-	initOutput, err := terraform.InitE(t, terraformOptions)
+	initOutput, err := helper.InitE(t, terraformOptions)
 	require.NoError(t, err, "Terraform init failed")
 	t.Log("✅ Terraform Init Output:\n", initOutput)
 
@@ -48,28 +49,25 @@ func TestValidationOnExamplesBasicWhenAllFeaturesEnabled(t *testing.T) {
 func TestPlanningOnExamplesBasicWhenDefaultFixture(t *testing.T) {
 	t.Parallel()
 
-	// Use helper function to setup terraform options with isolated provider cache
-	terraformOptions := helper.SetupTerraformOptions(t, "foundation/basic", nil)
-
-	// Add var files to the options
-	terraformOptions.VarFiles = []string{"fixtures/default.tfvars"}
+	// Setup terraform options with isolated provider cache and workspace for the fixture
+	terraformOptions := helper.NewTerraformOptions(t, helper.ExampleSource("foundation/basic"),
+		helper.WithWorkspaceCopy(),
+		helper.WithVarFiles("fixtures/default.tfvars"),
+	)
 
 	t.Logf("🔍 Terraform Example Directory: %s", terraformOptions.TerraformDir)
 	t.Logf("📝 Using fixture: fixtures/default.tfvars")
 
-	initOutput, err := terraform.InitE(t, terraformOptions)
-	require.NoError(t, err, "Terraform init failed")
-	t.Log("✅ Terraform Init Output:\n", initOutput)
-
-	planOutput, err := terraform.PlanE(t, terraformOptions)
-	require.NoError(t, err, "Terraform plan failed")
-	t.Log("📝 Terraform Plan Output:\n", planOutput)
+	// Initialize and save the plan so assertions run against its JSON representation
+	tfPlan, err := plan.InitAndPlanE(t, terraformOptions)
+	require.NoError(t, err, "Terraform init and plan failed")
+	t.Logf("📝 Planned resources: %v", tfPlan.Addresses())
 
-	// Verify plan contains expected resources
-	require.Contains(t, planOutput, "aws_kms_key", "Plan should include KMS key resource")
-	require.Contains(t, planOutput, "aws_kms_alias", "Plan should include KMS alias resource")
-	require.Contains(t, planOutput, "aws_s3_bucket", "Plan should include S3 bucket resource")
-	require.Contains(t, planOutput, "aws_cloudwatch_log_group", "Plan should include CloudWatch Log Group resource")
+	// Verify plan creates the expected resources
+	plan.RequireResourceAction(t, tfPlan, "module.this.aws_kms_key.this[0]", plan.ActionCreate)
+	plan.RequireResourceAction(t, tfPlan, "module.this.aws_kms_alias.this[0]", plan.ActionCreate)
+	plan.RequireResourceAction(t, tfPlan, "module.this.aws_s3_bucket.this[0]", plan.ActionCreate)
+	plan.RequireResourceAction(t, tfPlan, "module.this.aws_cloudwatch_log_group.this[0]", plan.ActionCreate)
 }
 
 // TestFormatCheckOnExamplesBasicWhenAllFeaturesEnabled verifies that the
@@ -77,12 +75,12 @@ func TestPlanningOnExamplesBasicWhenDefaultFixture(t *testing.T) {
 func TestFormatCheckOnExamplesBasicWhenAllFeaturesEnabled(t *testing.T) {
 	t.Parallel()
 
-	// Use helper function to setup terraform options with isolated provider cache
-	terraformOptions := helper.SetupTerraformOptions(t, "foundation/basic", nil)
+	// Use helper function to setup terraform options with isolated provider cache and workspace
+	terraformOptions := helper.SetupTerraformOptions(t, "foundation/basic", nil, helper.WithWorkspaceCopy())
 
 	t.Logf("🔍 Checking Terraform formatting in: %s", terraformOptions.TerraformDir)
 
-	initOutput, err := terraform.InitE(t, terraformOptions)
+	initOutput, err := helper.InitE(t, terraformOptions)
 	require.NoError(t, err, "Terraform init failed")
 	t.Log("✅ Terraform Init Output:\n", initOutput)
 
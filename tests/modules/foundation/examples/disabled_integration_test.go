@@ -4,9 +4,11 @@ package examples
 
 import (
 	"testing"
-	"time"
 
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/harness"
 	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/plan"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/waiter"
 	"github.com/gruntwork-io/terratest/modules/terraform"
 	"github.com/stretchr/testify/assert"
 )
@@ -16,53 +18,57 @@ import (
 func TestDeploymentOnExamplesBasicWhenDisabledFixture(t *testing.T) {
 	t.Parallel()
 
-	// Use helper function to setup terraform options with isolated provider cache
-	terraformOptions := helper.SetupTerraformOptions(t, "foundation/basic", nil)
+	harness.ForEachRegion(t, func(t *testing.T, awsCtx harness.Context) {
+		// Setup terraform options with isolated provider cache and workspace for the fixture in the test region
+		terraformOptions := helper.NewTerraformOptions(t, helper.ExampleSource("foundation/basic"),
+			helper.WithWorkspaceCopy(),
+			awsCtx.TerraformOption(),
+			helper.WithVarFiles("fixtures/disabled.tfvars"),
+		)
 
-	// Add var files to the options
-	terraformOptions.VarFiles = []string{"fixtures/disabled.tfvars"}
+		// Destroy resources when the test completes and wait until AWS reports them deleted
+		defer waiter.DestroyAndWait(t, terraformOptions, awsCtx.Region)
 
-	// Cleanup resources when the test completes
-	defer func() {
-		terraform.Destroy(t, terraformOptions)
-		helper.WaitForResourceDeletion(t, 10*time.Second)
-	}()
+		t.Logf("🔍 Terraform Example Directory: %s", terraformOptions.TerraformDir)
+		t.Logf("📝 Using fixture: fixtures/disabled.tfvars")
 
-	t.Logf("🔍 Terraform Example Directory: %s", terraformOptions.TerraformDir)
-	t.Logf("📝 Using fixture: fixtures/disabled.tfvars")
+		// Initialize and apply Terraform
+		helper.Init(t, terraformOptions)
+		terraform.Apply(t, terraformOptions)
 
-	// Initialize and apply Terraform
-	terraform.InitAndApply(t, terraformOptions)
+		// Verify a second plan is empty, so the configuration has no perpetual diff
+		plan.RequireIdempotent(t, terraformOptions)
 
-	// Get outputs from Terraform
-	isEnabledStr := terraform.Output(t, terraformOptions, "is_enabled")
-	isEnabled := isEnabledStr == "true"
+		// Get outputs from Terraform
+		isEnabledStr := terraform.Output(t, terraformOptions, "is_enabled")
+		isEnabled := isEnabledStr == "true"
 
-	// Verify the module is disabled
-	assert.False(t, isEnabled, "Expected module to be disabled with is_enabled=false")
+		// Verify the module is disabled
+		assert.False(t, isEnabled, "Expected module to be disabled with is_enabled=false")
 
-	// Verify feature flags
-	featureFlags := terraform.OutputMap(t, terraformOptions, "feature_flags")
-	assert.Equal(t, "false", featureFlags["is_enabled"], "Expected is_enabled feature flag to be false")
-	assert.Equal(t, "false", featureFlags["is_kms_key_enabled"], "Expected is_kms_key_enabled feature flag to be false")
-	assert.Equal(t, "false", featureFlags["is_s3_bucket_enabled"], "Expected is_s3_bucket_enabled feature flag to be false")
-	assert.Equal(t, "false", featureFlags["is_log_group_enabled"], "Expected is_log_group_enabled feature flag to be false")
+		// Verify feature flags
+		featureFlags := terraform.OutputMap(t, terraformOptions, "feature_flags")
+		assert.Equal(t, "false", featureFlags["is_enabled"], "Expected is_enabled feature flag to be false")
+		assert.Equal(t, "false", featureFlags["is_kms_key_enabled"], "Expected is_kms_key_enabled feature flag to be false")
+		assert.Equal(t, "false", featureFlags["is_s3_bucket_enabled"], "Expected is_s3_bucket_enabled feature flag to be false")
+		assert.Equal(t, "false", featureFlags["is_log_group_enabled"], "Expected is_log_group_enabled feature flag to be false")
 
-	// Verify outputs for resources are empty when module is disabled
-	outputs := []string{
-		"kms_key_arn",
-		"kms_key_id",
-		"s3_bucket_id",
-		"log_group_name",
-	}
+		// Verify outputs for resources are empty when module is disabled
+		outputs := []string{
+			"kms_key_arn",
+			"kms_key_id",
+			"s3_bucket_id",
+			"log_group_name",
+		}
 
-	for _, output := range outputs {
-		value, err := terraform.OutputE(t, terraformOptions, output)
-		if err == nil {
-			assert.Empty(t, value, "Expected output %q to be empty when module is disabled", output)
-		} else {
-			// If the output isn't defined in disabled state, that's also acceptable
-			t.Logf("Output %q is not available when module is disabled (expected behavior)", output)
+		for _, output := range outputs {
+			value, err := terraform.OutputE(t, terraformOptions, output)
+			if err == nil {
+				assert.Empty(t, value, "Expected output %q to be empty when module is disabled", output)
+			} else {
+				// If the output isn't defined in disabled state, that's also acceptable
+				t.Logf("Output %q is not available when module is disabled (expected behavior)", output)
+			}
 		}
-	}
+	})
 }
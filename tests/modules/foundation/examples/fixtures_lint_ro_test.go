@@ -0,0 +1,18 @@
+//go:build readonly && examples
+
+package examples
+
+import (
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/lint"
+)
+
+// TestValidationOnFoundationExampleFixturesWhenComparedToVariables verifies that every fixture of
+// every foundation example only assigns declared variables, with values of the declared type, and sets
+// every required variable.
+func TestValidationOnFoundationExampleFixturesWhenComparedToVariables(t *testing.T) {
+	t.Parallel()
+
+	lint.RequireValidFixtures(t, "foundation")
+}
@@ -0,0 +1,50 @@
+//go:build readonly && examples
+
+package examples
+
+import (
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/harness"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/oidc"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/plan"
+)
+
+// TestThumbprintOnFoundationAdvancedOIDCExampleWhenIssuerIsLocal plans the fixtures of the
+// advanced-oidc example that create an OIDC provider against a local issuer, and verifies that the
+// thumbprint the module reads through the tls_certificate data source is the fingerprint of the
+// issuer's generated CA.
+func TestThumbprintOnFoundationAdvancedOIDCExampleWhenIssuerIsLocal(t *testing.T) {
+	t.Parallel()
+
+	issuer := oidc.NewIssuer(t)
+	providerAddress := "module.this.aws_iam_openid_connect_provider.oidc[0]"
+
+	for _, fixture := range []string{"default", "advanced-oidc"} {
+		fixture := fixture
+
+		t.Run(fixture, func(t *testing.T) {
+			t.Parallel()
+
+			terraformOptions := helper.NewTerraformOptions(t, helper.ExampleSource("foundation/advanced-oidc"),
+				helper.WithWorkspaceCopy(),
+				harness.Load(t).TerraformOption(),
+				helper.WithNoColor(),
+				helper.WithVarFiles("fixtures/"+fixture+".tfvars"),
+				issuer.TerraformOption(),
+			)
+
+			t.Logf("🔍 Terraform Example Directory: %s", terraformOptions.TerraformDir)
+			t.Logf("📝 Using fixture: fixtures/%s.tfvars", fixture)
+
+			tfPlan := plan.InitAndPlan(t, terraformOptions)
+
+			plan.RequireResourceAction(t, tfPlan, providerAddress, plan.ActionCreate)
+			plan.RequireAfterAttribute(t, tfPlan, providerAddress, "url", issuer.URL)
+			plan.RequireAfterAttribute(t, tfPlan, providerAddress, "thumbprint_list", []interface{}{issuer.Thumbprint()})
+
+			t.Logf("✅ Planned thumbprint matches the issuer CA: %s", issuer.Thumbprint())
+		})
+	}
+}
@@ -0,0 +1,33 @@
+//go:build integration && examples
+
+package examples
+
+import (
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/contract"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/harness"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/naming"
+)
+
+// TestOutputContractOnFoundationModuleWhenApplied verifies that `terraform output -json` honours
+// the output contract of the foundation module once it is applied, enabled and disabled.
+func TestOutputContractOnFoundationModuleWhenApplied(t *testing.T) {
+	t.Parallel()
+
+	harness.ForEachRegion(t, func(t *testing.T, awsCtx harness.Context) {
+		for _, mode := range []contract.Mode{contract.ModeEnabled, contract.ModeDisabled} {
+			mode := mode
+
+			t.Run(string(mode), func(t *testing.T) {
+				t.Parallel()
+
+				contract.RunApplied(t, contract.Foundation, awsCtx, map[string]interface{}{
+					"kms_key_alias":  naming.Name(t, naming.KMSAlias, "alias/contract-codeartifact"),
+					"s3_bucket_name": naming.Name(t, naming.S3Bucket, "contract-codeartifact"),
+					"log_group_name": naming.Name(t, naming.LogGroup, "/aws/codeartifact/contract"),
+				}, mode)
+			})
+		}
+	})
+}
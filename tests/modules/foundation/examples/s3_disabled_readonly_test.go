@@ -6,7 +6,7 @@ import (
 	"testing"
 
 	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
-	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/plan"
 	"github.com/stretchr/testify/require"
 )
 
@@ -15,28 +15,25 @@ import (
 func TestPlanningOnExamplesBasicWhenS3DisabledFixture(t *testing.T) {
 	t.Parallel()
 
-	// Use helper function to setup terraform options with isolated provider cache
-	terraformOptions := helper.SetupTerraformOptions(t, "foundation/basic", nil)
-
-	// Add var files to the options
-	terraformOptions.VarFiles = []string{"fixtures/s3-disabled.tfvars"}
+	// Setup terraform options with isolated provider cache and workspace for the fixture
+	terraformOptions := helper.NewTerraformOptions(t, helper.ExampleSource("foundation/basic"),
+		helper.WithWorkspaceCopy(),
+		helper.WithVarFiles("fixtures/s3-disabled.tfvars"),
+	)
 
 	t.Logf("🔍 Terraform Example Directory: %s", terraformOptions.TerraformDir)
 	t.Logf("📝 Using fixture: fixtures/s3-disabled.tfvars")
 
-	initOutput, err := terraform.InitE(t, terraformOptions)
-	require.NoError(t, err, "Terraform init failed")
-	t.Log("✅ Terraform Init Output:\n", initOutput)
-
-	planOutput, err := terraform.PlanE(t, terraformOptions)
-	require.NoError(t, err, "Terraform plan failed")
-	t.Log("📝 Terraform Plan Output:\n", planOutput)
+	// Initialize and save the plan so assertions run against its JSON representation
+	tfPlan, err := plan.InitAndPlanE(t, terraformOptions)
+	require.NoError(t, err, "Terraform init and plan failed")
+	t.Logf("📝 Planned resources: %v", tfPlan.Addresses())
 
 	// Verify plan doesn't contain S3 resources when S3 component is disabled
-	require.NotContains(t, planOutput, "aws_s3_bucket", "Plan should not include S3 bucket resource when S3 component is disabled")
-	require.NotContains(t, planOutput, "aws_s3_bucket_policy", "Plan should not include S3 bucket policy resource when S3 component is disabled")
+	plan.RequireResourceNotPlanned(t, tfPlan, "module.this.aws_s3_bucket.this[0]")
+	plan.RequireResourceNotPlanned(t, tfPlan, "module.this.aws_s3_bucket_policy.this[0]")
 
-	// But plan should still contain KMS and CloudWatch Log Group resources
-	require.Contains(t, planOutput, "aws_kms_key", "Plan should include KMS key resource")
-	require.Contains(t, planOutput, "aws_cloudwatch_log_group", "Plan should include CloudWatch Log Group resource")
+	// But plan should still create the KMS and CloudWatch Log Group resources
+	plan.RequireResourceAction(t, tfPlan, "module.this.aws_kms_key.this[0]", plan.ActionCreate)
+	plan.RequireResourceAction(t, tfPlan, "module.this.aws_cloudwatch_log_group.this[0]", plan.ActionCreate)
 }
@@ -0,0 +1,30 @@
+//go:build integration && examples && upgrade
+
+package examples
+
+import (
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/harness"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/naming"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/upgrade"
+)
+
+// TestUpgradeOnExamplesBasicWhenPreviousReleaseIsApplied verifies that the working tree plans no
+// destroy or replacement of the KMS key and S3 bucket applied by the last release of the module.
+func TestUpgradeOnExamplesBasicWhenPreviousReleaseIsApplied(t *testing.T) {
+	t.Parallel()
+
+	harness.ForEachRegion(t, func(t *testing.T, awsCtx harness.Context) {
+		upgrade.Run(t, upgrade.Config{
+			Module:   "foundation",
+			Example:  "foundation/basic",
+			VarFiles: []string{"fixtures/default.tfvars"},
+			Vars: map[string]interface{}{
+				"kms_key_alias":  naming.Name(t, naming.KMSAlias, "alias/codeartifact-upgrade"),
+				"s3_bucket_name": naming.Name(t, naming.S3Bucket, "codeartifact-upgrade"),
+				"log_group_name": naming.Name(t, naming.LogGroup, "/aws/codeartifact/upgrade"),
+			},
+		}, awsCtx)
+	})
+}
@@ -0,0 +1,21 @@
+//go:build unit && readonly
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/contract"
+)
+
+// TestOutputContractOnFoundationModuleWhenEnabledOrDisabled verifies that the foundation module declares and
+// plans exactly the outputs of its contract, enabled and disabled.
+func TestOutputContractOnFoundationModuleWhenEnabledOrDisabled(t *testing.T) {
+	t.Parallel()
+
+	contract.Run(t, contract.Foundation, map[string]interface{}{
+		"kms_key_alias":  "alias/contract-codeartifact",
+		"s3_bucket_name": "contract-codeartifact-bucket",
+		"log_group_name": "/aws/codeartifact/contract",
+	})
+}
@@ -0,0 +1,195 @@
+//go:build unit && readonly
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/validation"
+)
+
+// TestValidationOnFoundationModuleWhenVariablesAreInvalid verifies that every validation block of the
+// foundation module rejects invalid values with its exact error message.
+func TestValidationOnFoundationModuleWhenVariablesAreInvalid(t *testing.T) {
+	t.Parallel()
+
+	const (
+		deletionWindowMessage = "The KMS key deletion window must be between 7 and 30 days."
+		oidcRolesMessage      = "Validation failed for one or more roles in oidc_roles. Check name format, max_session_duration (3600-43200), ensure condition_string_like is provided, attach_policy_arns format, and inline_policies JSON validity."
+		oidcProviderURL       = "https://token.actions.githubusercontent.com"
+		replicationRoleARN    = "arn:aws:iam::123456789012:role/validation-replication"
+	)
+
+	// oidcRole returns a valid role with the given attributes overridden.
+	oidcRole := func(overrides map[string]interface{}) []map[string]interface{} {
+		role := map[string]interface{}{
+			"name":                  "validation-oidc-role",
+			"max_session_duration":  3600,
+			"condition_string_like": map[string][]string{"token.actions.githubusercontent.com:sub": {"repo:example/example:*"}},
+			"attach_policy_arns":    []string{"arn:aws:iam::aws:policy/ReadOnlyAccess"},
+			"inline_policies":       map[string]string{"validation": `{"Version": "2012-10-17", "Statement": []}`},
+		}
+
+		for key, value := range overrides {
+			role[key] = value
+		}
+
+		return []map[string]interface{}{role}
+	}
+
+	// bucketPolicy returns a valid additional bucket policy statement with the given sid and effect.
+	bucketPolicy := func(sid, effect string) []map[string]interface{} {
+		return []map[string]interface{}{{
+			"sid":        sid,
+			"effect":     effect,
+			"actions":    []string{"s3:GetObject"},
+			"principals": map[string]interface{}{"type": "AWS", "identifiers": []string{"arn:aws:iam::123456789012:root"}},
+			"resources":  []string{"arn:aws:s3:::validation-bucket/*"},
+		}}
+	}
+
+	validation.Run(t, validation.Matrix{
+		Module: "foundation",
+		Vars: map[string]interface{}{
+			"kms_key_alias":  "alias/validation-codeartifact",
+			"s3_bucket_name": "validation-codeartifact-bucket",
+			"log_group_name": "/aws/codeartifact/validation",
+		},
+		Cases: []validation.Case{
+			{
+				Name:         "below-minimum",
+				Variable:     "kms_key_deletion_window",
+				Vars:         map[string]interface{}{"kms_key_deletion_window": 6},
+				ErrorMessage: deletionWindowMessage,
+			},
+			{
+				Name:         "above-maximum",
+				Variable:     "kms_key_deletion_window",
+				Vars:         map[string]interface{}{"kms_key_deletion_window": 31},
+				ErrorMessage: deletionWindowMessage,
+			},
+			{
+				Name:         "missing-prefix",
+				Variable:     "kms_key_alias",
+				Vars:         map[string]interface{}{"kms_key_alias": "validation-codeartifact"},
+				ErrorMessage: "The KMS key alias must begin with 'alias/'.",
+			},
+			{
+				Name:         "malformed-json",
+				Variable:     "kms_key_policy",
+				Vars:         map[string]interface{}{"kms_key_policy": `{"Version": "2012-10-17"`},
+				ErrorMessage: "The KMS key policy must be a valid JSON string.",
+			},
+			{
+				Name:         "unsupported-period",
+				Variable:     "log_group_retention_days",
+				Vars:         map[string]interface{}{"log_group_retention_days": 2},
+				ErrorMessage: "Log group retention days must be one of [0, 1, 3, 5, 7, 14, 30, 60, 90, 120, 150, 180, 365, 400, 545, 731, 1827, 3653].",
+			},
+			{
+				Name:         "uppercase",
+				Variable:     "s3_bucket_name",
+				Vars:         map[string]interface{}{"s3_bucket_name": "Validation_Bucket"},
+				ErrorMessage: "S3 bucket name must be between 3 and 63 characters, start and end with a letter or number, and contain only lowercase letters, numbers, dots, and hyphens.",
+			},
+			{
+				Name:         "invalid-sid",
+				Variable:     "additional_bucket_policies",
+				Vars:         map[string]interface{}{"additional_bucket_policies": bucketPolicy("Invalid Sid!", "Allow")},
+				ErrorMessage: "All policy statement IDs (sid) must be alphanumeric with optional underscores or hyphens.",
+			},
+			{
+				Name:         "invalid-effect",
+				Variable:     "additional_bucket_policies",
+				Vars:         map[string]interface{}{"additional_bucket_policies": bucketPolicy("ValidationSid", "Permit")},
+				ErrorMessage: "Policy effect must be either 'Allow' or 'Deny'.",
+			},
+			{
+				Name:     "missing-when-replicating",
+				Variable: "s3_replication_role_arn",
+				Vars: map[string]interface{}{
+					"is_s3_replication_enabled":  true,
+					"s3_replication_destination": map[string]interface{}{"bucket_arn": "arn:aws:s3:::validation-replica"},
+				},
+				ErrorMessage: "s3_replication_role_arn must be provided when is_s3_replication_enabled is true.",
+			},
+			{
+				Name:         "user-arn",
+				Variable:     "s3_replication_role_arn",
+				Vars:         map[string]interface{}{"s3_replication_role_arn": "arn:aws:iam::123456789012:user/validation"},
+				ErrorMessage: "s3_replication_role_arn must be a valid IAM role ARN.",
+			},
+			{
+				Name:     "missing-when-replicating",
+				Variable: "s3_replication_destination",
+				Vars: map[string]interface{}{
+					"is_s3_replication_enabled": true,
+					"s3_replication_role_arn":   replicationRoleARN,
+				},
+				ErrorMessage: "s3_replication_destination must be provided when is_s3_replication_enabled is true.",
+			},
+			{
+				Name:         "bucket-name",
+				Variable:     "s3_replication_destination",
+				Vars:         map[string]interface{}{"s3_replication_destination": map[string]interface{}{"bucket_arn": "validation-replica"}},
+				ErrorMessage: "s3_replication_destination.bucket_arn must be a valid S3 bucket ARN (e.g., arn:aws:s3:::bucket-name).",
+			},
+			{
+				Name:     "http-url",
+				Variable: "oidc_provider_url",
+				Vars: map[string]interface{}{
+					"is_oidc_provider_enabled": true,
+					"oidc_provider_url":        "http://token.actions.githubusercontent.com",
+					"oidc_roles":               oidcRole(nil),
+				},
+				ErrorMessage: "If OIDC provider is enabled, oidc_provider_url must be provided and start with 'https://'.",
+			},
+			{
+				Name:     "no-roles",
+				Variable: "oidc_roles",
+				Vars: map[string]interface{}{
+					"is_oidc_provider_enabled": true,
+					"oidc_provider_url":        oidcProviderURL,
+					"oidc_roles":               []map[string]interface{}{},
+				},
+				ErrorMessage: "If is_oidc_provider_enabled is true, at least one role must be defined in oidc_roles.",
+			},
+			{
+				Name:         "invalid-name",
+				Variable:     "oidc_roles",
+				Vars:         map[string]interface{}{"oidc_roles": oidcRole(map[string]interface{}{"name": "validation role!"})},
+				ErrorMessage: oidcRolesMessage,
+			},
+			{
+				Name:         "short-session",
+				Variable:     "oidc_roles",
+				Vars:         map[string]interface{}{"oidc_roles": oidcRole(map[string]interface{}{"max_session_duration": 1800})},
+				ErrorMessage: oidcRolesMessage,
+			},
+			{
+				Name:         "long-session",
+				Variable:     "oidc_roles",
+				Vars:         map[string]interface{}{"oidc_roles": oidcRole(map[string]interface{}{"max_session_duration": 43201})},
+				ErrorMessage: oidcRolesMessage,
+			},
+			{
+				Name:         "empty-conditions",
+				Variable:     "oidc_roles",
+				Vars:         map[string]interface{}{"oidc_roles": oidcRole(map[string]interface{}{"condition_string_like": map[string][]string{}})},
+				ErrorMessage: oidcRolesMessage,
+			},
+			{
+				Name:         "invalid-policy-arn",
+				Variable:     "oidc_roles",
+				Vars:         map[string]interface{}{"oidc_roles": oidcRole(map[string]interface{}{"attach_policy_arns": []string{"ReadOnlyAccess"}})},
+				ErrorMessage: oidcRolesMessage,
+			},
+			{
+				Name:         "malformed-inline-policy",
+				Variable:     "oidc_roles",
+				Vars:         map[string]interface{}{"oidc_roles": oidcRole(map[string]interface{}{"inline_policies": map[string]string{"validation": "{"}})},
+				ErrorMessage: oidcRolesMessage,
+			},
+		},
+	})
+}
@@ -0,0 +1,75 @@
+//go:build readonly && examples
+
+package examples
+
+import (
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/iampolicy"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/plan"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/recipe"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/repo"
+	"github.com/stretchr/testify/require"
+)
+
+// plannedPolicy returns the policy generated by the module. The domain owner comes from the
+// example's repository, so the statements are taken from the deferred data source read when the
+// planned policy_document is not yet known.
+func plannedPolicy(t *testing.T, tfPlan *plan.Plan) *iampolicy.Document {
+	t.Helper()
+
+	if doc, err := iampolicy.FromPlan(tfPlan, "module.this[0].aws_codeartifact_repository_permissions_policy.this[0]", "policy_document"); err == nil {
+		return doc
+	}
+
+	doc, err := iampolicy.FromPolicyDocumentData(tfPlan, "module.this[0].data.aws_iam_policy_document.combined[0]")
+	require.NoError(t, err, "Deferred policy document read should be planned")
+
+	return doc
+}
+
+// TestPlanningOnRepositoryPermissionsExampleWhenAllRecipesAreUsed verifies the Terraform plan generation
+// for every repository-permissions example with every fixture recipe discovered in its fixtures/ directory.
+func TestPlanningOnRepositoryPermissionsExampleWhenAllRecipesAreUsed(t *testing.T) {
+	t.Parallel()
+
+	recipe.Run(t, "repository-permissions", recipe.Config{
+		Golden: true,
+		Assert: assertRepositoryPermissionsRecipe,
+	})
+}
+
+// assertRepositoryPermissionsRecipe checks the plan of the basic example according to the fixture used.
+// Recipes of the other examples only need to plan successfully.
+func assertRepositoryPermissionsRecipe(t *testing.T, example repo.Example, fixture repo.Fixture, tfPlan *plan.Plan) {
+	if example.Path != "repository-permissions/basic" {
+		return
+	}
+
+	domainAddress := "aws_codeartifact_domain.example[0]"
+	repositoryAddress := "module.repository[0].aws_codeartifact_repository.this[0]"
+	policyAddress := "module.this[0].aws_codeartifact_repository_permissions_policy.this[0]"
+
+	if fixture.Name == "disabled" {
+		// When disabled, neither the example's domain and repository nor the module's policy are planned
+		plan.RequireResourceNotPlanned(t, tfPlan, policyAddress)
+		plan.RequireNoManagedResources(t, tfPlan)
+
+		return
+	}
+
+	// For enabled fixtures, the example creates the domain and repository the policy is attached to
+	plan.RequireResourceAction(t, tfPlan, domainAddress, plan.ActionCreate)
+	plan.RequireResourceAction(t, tfPlan, repositoryAddress, plan.ActionCreate)
+	plan.RequireResourceAction(t, tfPlan, policyAddress, plan.ActionCreate)
+
+	// The example only sets read principals, so the other baseline statements are left out
+	policy := plannedPolicy(t, tfPlan)
+	iampolicy.RequireSIDs(t, policy, "BaselineReadAccess", "AllowCallerPublish")
+
+	iampolicy.RequireStatementMatches(t, policy, "AllowCallerPublish", iampolicy.Expected{
+		Effect:    "Allow",
+		Actions:   []string{"codeartifact:PublishPackageVersion", "codeartifact:PutPackageMetadata"},
+		Resources: []string{"*"},
+	})
+}
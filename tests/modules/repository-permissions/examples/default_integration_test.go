@@ -0,0 +1,84 @@
+//go:build integration && examples
+
+package examples
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/harness"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/iampolicy"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/naming"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/plan"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/waiter"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/codeartifact"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDeploymentOnRepositoryPermissionsExampleWhenDefaultFixture applies the basic example with the
+// default.tfvars fixture, then reads the policy back from AWS and checks it is the policy document
+// and revision the module reports.
+func TestDeploymentOnRepositoryPermissionsExampleWhenDefaultFixture(t *testing.T) {
+	t.Parallel()
+
+	harness.ForEachRegion(t, func(t *testing.T, awsCtx harness.Context) {
+		// Setup terraform options with isolated provider cache and workspace for the fixture in the test region
+		terraformOptions := helper.NewTerraformOptions(t, helper.ExampleSource("repository-permissions/basic"),
+			helper.WithWorkspaceCopy(),
+			awsCtx.TerraformOption(),
+			helper.WithVarFiles("fixtures/default.tfvars"),
+			helper.WithVars(map[string]interface{}{
+				"domain_name":     naming.Name(t, naming.CodeArtifactDomain, "repository-permissions"),
+				"repository_name": naming.Name(t, naming.CodeArtifactRepository, "repository-permissions"),
+			}),
+		)
+
+		// Destroy resources when the test completes and wait until AWS reports them deleted
+		defer waiter.DestroyAndWait(t, terraformOptions, awsCtx.Region)
+
+		t.Logf("🔍 Terraform Example Directory: %s", terraformOptions.TerraformDir)
+		t.Logf("📝 Using fixture: fixtures/default.tfvars")
+
+		// Initialize and apply Terraform
+		helper.Init(t, terraformOptions)
+		terraform.Apply(t, terraformOptions)
+
+		// Verify a second plan is empty, so the configuration has no perpetual diff
+		plan.RequireIdempotent(t, terraformOptions)
+
+		isEnabled := terraform.Output(t, terraformOptions, "repository_permissions_module_is_enabled")
+		require.Equal(t, "true", isEnabled, "The repository permissions module should be enabled")
+
+		domainName := terraform.Output(t, terraformOptions, "example_domain_name")
+		domainOwner := terraform.Output(t, terraformOptions, "example_domain_owner")
+		repositoryName := terraform.Output(t, terraformOptions, "repository_module_repository_name")
+		resourceARN := terraform.Output(t, terraformOptions, "repository_permissions_module_resource_arn")
+		revision := terraform.Output(t, terraformOptions, "repository_permissions_module_policy_revision")
+		expected := iampolicy.FromOutput(t, terraformOptions, "repository_permissions_module_policy_document")
+
+		// Read the policy AWS stores for the repository
+		client := codeartifact.NewFromConfig(awsCtx.AWSConfig(t, terraformOptions))
+
+		out, err := client.GetRepositoryPermissionsPolicy(context.Background(), &codeartifact.GetRepositoryPermissionsPolicyInput{
+			Domain:      aws.String(domainName),
+			DomainOwner: aws.String(domainOwner),
+			Repository:  aws.String(repositoryName),
+		})
+		require.NoError(t, err, "Failed to get the repository permissions policy")
+		require.NotNil(t, out.Policy, "Repository %s should have a permissions policy", repositoryName)
+
+		actual, err := iampolicy.Parse(aws.ToString(out.Policy.Document))
+		require.NoError(t, err, "Repository permissions policy should be a valid policy document")
+
+		// AWS may reorder statements and collapse single values, so the documents are compared semantically
+		iampolicy.RequireEquivalent(t, expected, actual)
+		t.Log("✅ Repository permissions policy in AWS matches the policy_document output")
+
+		// The revision output changes on every policy update and is used for optimistic locking
+		require.Equal(t, aws.ToString(out.Policy.Revision), revision, "The policy_revision output should be the revision AWS reports")
+		require.Equal(t, aws.ToString(out.Policy.ResourceArn), resourceARN, "The resource_arn output should be the repository AWS reports")
+	})
+}
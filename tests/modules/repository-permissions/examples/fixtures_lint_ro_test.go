@@ -0,0 +1,18 @@
+//go:build readonly && examples
+
+package examples
+
+import (
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/lint"
+)
+
+// TestValidationOnRepositoryPermissionsExampleFixturesWhenComparedToVariables verifies that every fixture of
+// every repository-permissions example only assigns declared variables, with values of the declared type, and sets
+// every required variable.
+func TestValidationOnRepositoryPermissionsExampleFixturesWhenComparedToVariables(t *testing.T) {
+	t.Parallel()
+
+	lint.RequireValidFixtures(t, "repository-permissions")
+}
@@ -0,0 +1,112 @@
+//go:build unit && readonly
+
+package unit
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/harness"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/iampolicy"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/plan"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/repo"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAuthorizationOnRepositoryPermissionsModuleWhenBaselinePrincipalsAreSet plans the module with
+// principals for every baseline statement and conditional custom statements, then evaluates the
+// planned policy offline to check who can call which repository action.
+func TestAuthorizationOnRepositoryPermissionsModuleWhenBaselinePrincipalsAreSet(t *testing.T) {
+	t.Parallel()
+
+	const (
+		domainName     = "authorization-domain"
+		repositoryName = "authorization-repository"
+		domainOwner    = "111122223333"
+		reader         = "arn:aws:iam::444455556666:role/codeartifact-reader"
+		describer      = "arn:aws:iam::444455556666:role/codeartifact-describer"
+		publisher      = "arn:aws:iam::444455556666:role/codeartifact-ci"
+		outsider       = "arn:aws:iam::777788889999:role/codeartifact-ci"
+	)
+
+	awsCtx := harness.Load(t)
+	arnPrefix := fmt.Sprintf("arn:%s:codeartifact:%s:%s", awsCtx.Partition, awsCtx.Region, domainOwner)
+	domainARN := fmt.Sprintf("%s:domain/%s", arnPrefix, domainName)
+	repositoryARN := fmt.Sprintf("%s:repository/%s/%s", arnPrefix, domainName, repositoryName)
+	packageARN := fmt.Sprintf("%s:package/%s/%s/npm//left-pad", arnPrefix, domainName, repositoryName)
+
+	dirs, err := repo.NewTFSourcesDir()
+	require.NoError(t, err, "Failed to get Terraform sources directory")
+
+	terraformOptions := helper.NewTerraformOptions(t, helper.ModuleSource(dirs.GetModulesDir("repository-permissions")),
+		awsCtx.TerraformOption(),
+		helper.WithWorkspaceCopy(),
+		helper.WithNoColor(),
+		helper.WithVars(map[string]interface{}{
+			"domain_name":                    domainName,
+			"repository_name":                repositoryName,
+			"domain_owner":                   domainOwner,
+			"read_principals":                []string{reader},
+			"describe_principals":            []string{describer},
+			"authorization_token_principals": []string{publisher},
+			"custom_policy_statements": []map[string]interface{}{
+				{
+					"Sid":       "DenyReaderReadmes",
+					"Effect":    "Deny",
+					"Principal": map[string]interface{}{"Type": "AWS", "Identifiers": []string{"*"}},
+					"Action":    []string{"codeartifact:GetPackageVersion*"},
+					"Resource":  []string{"*"},
+					"Condition": map[string]interface{}{
+						"Test":     "StringLike",
+						"Variable": iampolicy.PrincipalARNKey,
+						"Values":   []string{"arn:aws:iam::444455556666:role/codeartifact-read*"},
+					},
+				},
+				{
+					"Sid":       "AllowAccountPublishing",
+					"Effect":    "Allow",
+					"Principal": map[string]interface{}{"Type": "AWS", "Identifiers": []string{"*"}},
+					"Action":    []string{"codeartifact:PublishPackageVersion"},
+					"Resource":  []string{fmt.Sprintf("%s:package/%s/%s/*", arnPrefix, domainName, repositoryName)},
+					"Condition": map[string]interface{}{
+						"Test":     "StringEquals",
+						"Variable": iampolicy.PrincipalAccountKey,
+						"Values":   []string{"444455556666"},
+					},
+				},
+			},
+		}),
+	)
+
+	t.Logf("🔍 Terraform Module Directory: %s", terraformOptions.TerraformDir)
+
+	tfPlan := plan.InitAndPlan(t, terraformOptions)
+	policy := iampolicy.RequirePlannedDocument(t, tfPlan, "aws_codeartifact_repository_permissions_policy.this[0]", "policy_document")
+
+	cases := []struct {
+		name      string
+		principal string
+		action    string
+		resource  string
+		expected  iampolicy.Decision
+	}{
+		{"reader reads packages", reader, "codeartifact:ReadFromRepository", repositoryARN, iampolicy.Allow},
+		{"reader lists package versions", reader, "codeartifact:ListPackageVersions", repositoryARN, iampolicy.Allow},
+		{"reader is denied readmes", reader, "codeartifact:GetPackageVersionReadme", repositoryARN, iampolicy.ExplicitDeny},
+		{"reader describes nothing", reader, "codeartifact:DescribeRepository", repositoryARN, iampolicy.ImplicitDeny},
+		{"describer describes the repository", describer, "codeartifact:DescribeRepository", repositoryARN, iampolicy.Allow},
+		{"describer lists packages", describer, "codeartifact:ListPackages", repositoryARN, iampolicy.Allow},
+		{"describer reads no packages", describer, "codeartifact:ReadFromRepository", repositoryARN, iampolicy.ImplicitDeny},
+		{"publisher gets a domain token", publisher, "codeartifact:GetAuthorizationToken", domainARN, iampolicy.Allow},
+		{"publisher gets no repository token", publisher, "codeartifact:GetAuthorizationToken", repositoryARN, iampolicy.ImplicitDeny},
+		{"publisher publishes from the account", publisher, "codeartifact:PublishPackageVersion", packageARN, iampolicy.Allow},
+		{"outsider publishes nothing", outsider, "codeartifact:PublishPackageVersion", packageARN, iampolicy.ImplicitDeny},
+		{"outsider gets no token", outsider, "codeartifact:GetAuthorizationToken", domainARN, iampolicy.ImplicitDeny},
+	}
+
+	for _, c := range cases {
+		iampolicy.RequireDecision(t, policy, iampolicy.Request{Principal: c.principal, Action: c.action, Resource: c.resource}, c.expected)
+		t.Logf("✅ %s: %s", c.name, c.expected)
+	}
+}
@@ -0,0 +1,20 @@
+//go:build unit && readonly
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/contract"
+)
+
+// TestOutputContractOnRepositoryPermissionsModuleWhenEnabledOrDisabled verifies that the repository-permissions module declares and
+// plans exactly the outputs of its contract, enabled and disabled.
+func TestOutputContractOnRepositoryPermissionsModuleWhenEnabledOrDisabled(t *testing.T) {
+	t.Parallel()
+
+	contract.Run(t, contract.RepositoryPermissions, map[string]interface{}{
+		"domain_name":     "contract-domain",
+		"repository_name": "contract-repository",
+	})
+}
@@ -0,0 +1,77 @@
+//go:build unit && readonly
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/validation"
+)
+
+// TestValidationOnRepositoryPermissionsModuleWhenVariablesAreInvalid verifies that every validation
+// block of the repository-permissions module rejects invalid values with its exact error message.
+func TestValidationOnRepositoryPermissionsModuleWhenVariablesAreInvalid(t *testing.T) {
+	t.Parallel()
+
+	const repositoryNameMessage = "The repository name must be between 2 and 100 characters, start with a letter or number, and contain only letters, numbers, and the following characters: . _ / # = + - @"
+
+	validation.Run(t, validation.Matrix{
+		Module: "repository-permissions",
+		Vars: map[string]interface{}{
+			"domain_name":     "validation-domain",
+			"repository_name": "validation-repository",
+		},
+		Cases: []validation.Case{
+			{
+				Name:         "uppercase",
+				Variable:     "domain_name",
+				Vars:         map[string]interface{}{"domain_name": "Validation_Domain"},
+				ErrorMessage: "The domain name must be between 2-50 characters, contain only lowercase letters, numbers, and hyphens, cannot start or end with a hyphen.",
+			},
+			{
+				Name:         "too-short",
+				Variable:     "repository_name",
+				Vars:         map[string]interface{}{"repository_name": "r"},
+				ErrorMessage: repositoryNameMessage,
+			},
+			{
+				Name:         "leading-hyphen",
+				Variable:     "repository_name",
+				Vars:         map[string]interface{}{"repository_name": "-validation-repository"},
+				ErrorMessage: repositoryNameMessage,
+			},
+			{
+				Name:         "short-account-id",
+				Variable:     "domain_owner",
+				Vars:         map[string]interface{}{"domain_owner": "12345"},
+				ErrorMessage: "The domain_owner must be a 12-digit AWS account ID or null.",
+			},
+			{
+				Name:         "not-an-arn",
+				Variable:     "read_principals",
+				Vars:         map[string]interface{}{"read_principals": []string{"123456789012"}},
+				ErrorMessage: "Each item in read_principals must be a valid IAM principal ARN (account root, user, or role) or '*'.",
+			},
+			{
+				Name:         "group-principal",
+				Variable:     "describe_principals",
+				Vars:         map[string]interface{}{"describe_principals": []string{"arn:aws:iam::123456789012:group/developers"}},
+				ErrorMessage: "Each item in describe_principals must be a valid IAM principal ARN (account root, user, or role) or '*'.",
+			},
+			{
+				Name:         "short-account-id",
+				Variable:     "authorization_token_principals",
+				Vars:         map[string]interface{}{"authorization_token_principals": []string{"arn:aws:iam::123:role/ci"}},
+				ErrorMessage: "Each item in authorization_token_principals must be a valid IAM principal ARN (account root, user, or role) or '*'.",
+			},
+			{
+				Name:     "missing-action",
+				Variable: "custom_policy_statements",
+				Vars: map[string]interface{}{"custom_policy_statements": []map[string]interface{}{
+					{"Effect": "Allow", "Resource": []string{"*"}},
+				}},
+				ErrorMessage: "Each custom_policy_statement must be an object containing at least 'Effect' ('Allow' or 'Deny'), 'Action' (list of strings), and 'Resource' (list of strings).",
+			},
+		},
+	})
+}
@@ -3,110 +3,58 @@ package examples
 import (
 	"testing"
 
-	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
-	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/recipe"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/repo"
 	"github.com/stretchr/testify/require"
 )
 
-// TestPlanningOnRepositoryExampleWhenAllRecipesAreUsed verifies the Terraform plan generation
-// for the repository basic example with all available fixture recipes.
-func TestPlanningOnRepositoryExampleWhenAllRecipesAreUsed(t *testing.T) {
-	t.Parallel()
+// basicExamplePath is the repository example covered by TestPlanningOnRepositoryExampleWhenAllRecipesAreUsed.
+// Every other repository example is covered by TestPlanningOnAdvancedRepositoryExamplesWhenActive.
+const basicExamplePath = "repository/basic"
 
-	// Define fixtures to test
-	fixtures := []string{
-		"default.tfvars",
-		"disabled.tfvars",
-	}
+// discoverRepositoryExamples returns the repository examples split into the basic example and the rest.
+func discoverRepositoryExamples(t *testing.T) (basic, advanced []repo.Example) {
+	t.Helper()
 
-	for _, fixture := range fixtures {
-		// Using local variable to ensure proper capture in closure
-		fixture := fixture
+	dirs, err := repo.NewTFSourcesDir()
+	require.NoError(t, err, "Failed to get Terraform sources directory")
 
-		// Create a subtest for each fixture
-		t.Run(fixture, func(t *testing.T) {
-			t.Parallel()
+	examples, err := dirs.ListExamples("repository")
+	require.NoError(t, err, "Failed to discover repository examples")
 
-			// Use helper function to setup terraform options with isolated provider cache
-			terraformOptions := helper.SetupTerraformOptions(t, "repository/basic", nil)
-
-			// Add Upgrade=true to ensure modules are installed during init
-			terraformOptions.Upgrade = true
-
-			// Add var file to the options
-			terraformOptions.VarFiles = []string{"fixtures/" + fixture}
+	for _, example := range examples {
+		if example.Path == basicExamplePath {
+			basic = append(basic, example)
+		} else {
+			advanced = append(advanced, example)
+		}
+	}
 
-			t.Logf("🔍 Terraform Example Directory: %s", terraformOptions.TerraformDir)
-			t.Logf("📝 Using fixture: fixtures/%s", fixture)
+	return basic, advanced
+}
 
-			initOutput, err := terraform.InitE(t, terraformOptions)
-			require.NoError(t, err, "Terraform init failed")
-			t.Log("✅ Terraform Init Output:\n", initOutput)
+// TestPlanningOnRepositoryExampleWhenAllRecipesAreUsed verifies the Terraform plan generation
+// for the repository basic example with every fixture recipe discovered in its fixtures/ directory.
+func TestPlanningOnRepositoryExampleWhenAllRecipesAreUsed(t *testing.T) {
+	t.Parallel()
 
-			planOutput, err := terraform.PlanE(t, terraformOptions)
-			require.NoError(t, err, "Terraform plan failed")
-			t.Log("📝 Terraform Plan Output:\n", planOutput)
+	basic, _ := discoverRepositoryExamples(t)
+	require.Len(t, basic, 1, "Expected to discover the %s example", basicExamplePath)
 
-			// No assertions on plan content - we just want to verify the plan succeeds
-		})
-	}
+	// No assertions on plan content - the plan must succeed and match its golden file.
+	// Upgrade ensures modules are installed during init.
+	recipe.RunExamples(t, basic, recipe.Config{Upgrade: true, Golden: true})
 }
 
 // TestPlanningOnAdvancedRepositoryExamplesWhenActive verifies the Terraform plan generation
-// for all advanced repository examples.
+// for every other repository example with every fixture recipe discovered in its fixtures/ directory.
 func TestPlanningOnAdvancedRepositoryExamplesWhenActive(t *testing.T) {
 	t.Parallel()
 
-	// Define examples to test
-	examples := []string{
-		"repository/advanced-with-upstream",
-		"repository/advanced-with-policies",
-		"repository/advanced-with-connections",
-		"repository/advanced-complete",
-	}
+	_, advanced := discoverRepositoryExamples(t)
+	require.NotEmpty(t, advanced, "Expected to discover advanced repository examples")
 
-	fixtures := []string{
-		"default.tfvars",
-		"disabled.tfvars",
-	}
-
-	for _, examplePath := range examples {
-		// Using local variable to ensure proper capture in closure
-		examplePath := examplePath
-
-		for _, fixture := range fixtures {
-			// Using local variable to ensure proper capture in closure
-			fixture := fixture
-
-			// Create a subtest for each example and fixture combination
-			testName := examplePath + "-" + fixture
-			t.Run(testName, func(t *testing.T) {
-				t.Parallel()
-
-				// Use helper function to setup terraform options with isolated provider cache
-				terraformOptions := helper.SetupTerraformOptions(t, examplePath, nil)
-
-				// Add Upgrade=true to ensure modules are installed during init
-				terraformOptions.Upgrade = true
-
-				// Add var file to the options
-				terraformOptions.VarFiles = []string{"fixtures/" + fixture}
-
-				t.Logf("🔍 Terraform Example Directory: %s", terraformOptions.TerraformDir)
-				t.Logf("📝 Using fixture: fixtures/%s", fixture)
-
-				// Initialize Terraform - verify this succeeds
-				initOutput, err := terraform.InitE(t, terraformOptions)
-				require.NoError(t, err, "Terraform init failed")
-				t.Log("✅ Terraform Init Output:\n", initOutput)
-
-				// Plan Terraform configuration - just verify the plan succeeds
-				planOutput, err := terraform.PlanE(t, terraformOptions)
-				require.NoError(t, err, "Terraform plan failed")
-				t.Log("📝 Terraform Plan Output:\n", planOutput)
-
-				// No assertions on plan content - we just want to verify the plan succeeds
-			})
-		}
-	}
+	// No assertions on plan content - the plan must succeed and match its golden file.
+	// Upgrade ensures modules are installed during init.
+	recipe.RunExamples(t, advanced, recipe.Config{Upgrade: true, Golden: true})
 }
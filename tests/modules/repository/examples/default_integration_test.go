@@ -1,10 +1,15 @@
+//go:build integration && examples
+
 package examples
 
 import (
 	"testing"
-	"time"
 
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/harness"
 	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/naming"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/plan"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/waiter"
 	"github.com/gruntwork-io/terratest/modules/terraform"
 	"github.com/stretchr/testify/require"
 )
@@ -14,49 +19,49 @@ import (
 func TestDeploymentOnRepositoryExampleWhenDefaultFixture(t *testing.T) {
 	t.Parallel()
 
-	// Use helper function to setup terraform options with isolated provider cache
-	terraformOptions := helper.SetupTerraformOptions(t, "repository/basic", nil)
-
-	// Add var file to the options for the default fixture
-	terraformOptions.VarFiles = []string{"fixtures/default.tfvars"}
+	harness.ForEachRegion(t, func(t *testing.T, awsCtx harness.Context) {
+		// Setup terraform options with isolated provider cache and workspace for the fixture in the test region
+		terraformOptions := helper.NewTerraformOptions(t, helper.ExampleSource("repository/basic"),
+			helper.WithWorkspaceCopy(),
+			awsCtx.TerraformOption(),
+			helper.WithVarFiles("fixtures/default.tfvars"),
+			helper.WithVars(map[string]interface{}{
+				"domain_name":     naming.Name(t, naming.CodeArtifactDomain, "example-basic-domain"),
+				"repository_name": naming.Name(t, naming.CodeArtifactRepository, "my-basic-repository-example"),
+			}),
+		)
 
-	// Cleanup resources when the test completes
-	defer func() {
-		terraform.Destroy(t, terraformOptions)
-		helper.WaitForResourceDeletion(t, 7*time.Second)
-	}()
+		// Destroy resources when the test completes and wait until AWS reports them deleted
+		defer waiter.DestroyAndWait(t, terraformOptions, awsCtx.Region)
 
-	t.Logf("🔍 Terraform Example Directory: %s", terraformOptions.TerraformDir)
-	t.Logf("📝 Using fixture: fixtures/default.tfvars")
+		t.Logf("🔍 Terraform Example Directory: %s", terraformOptions.TerraformDir)
+		t.Logf("📝 Using fixture: fixtures/default.tfvars")
 
-	// Initialize Terraform
-	initOutput, err := terraform.InitE(t, terraformOptions)
-	require.NoError(t, err, "Terraform init failed")
-	t.Log("✅ Terraform Init Output:\n", initOutput)
+		// Initialize and plan Terraform
+		tfPlan := plan.InitAndPlan(t, terraformOptions)
+		t.Logf("📝 Planned resources: %v", tfPlan.Addresses())
 
-	// Plan Terraform configuration
-	planOutput, err := terraform.PlanE(t, terraformOptions)
-	require.NoError(t, err, "Terraform plan failed")
-	t.Log("📝 Terraform Plan Output:\n", planOutput)
+		// Verify that the repository is created when module is enabled with default fixture
+		plan.RequireResourceAction(t, tfPlan, "module.this.aws_codeartifact_repository.this[0]", plan.ActionCreate)
 
-	// Verify that repository resources are planned when module is enabled with default fixture
-	require.Contains(t, planOutput, "aws_codeartifact_repository.this",
-		"CodeArtifact repository resource should be planned when module is enabled")
+		// Apply Terraform configuration
+		applyOutput, err := terraform.ApplyE(t, terraformOptions)
+		require.NoError(t, err, "Terraform apply failed")
+		t.Log("✅ Terraform Apply Output:\n", applyOutput)
 
-	// Apply Terraform configuration
-	applyOutput, err := terraform.ApplyE(t, terraformOptions)
-	require.NoError(t, err, "Terraform apply failed")
-	t.Log("✅ Terraform Apply Output:\n", applyOutput)
+		// Verify a second plan is empty, so the configuration has no perpetual diff
+		plan.RequireIdempotent(t, terraformOptions)
 
-	// Verify the is_enabled output is true
-	isEnabledOutput := terraform.Output(t, terraformOptions, "is_enabled")
-	require.Equal(t, "true", isEnabledOutput, "The is_enabled output should be true when the module is enabled")
+		// Verify the is_enabled output is true
+		isEnabledOutput := terraform.Output(t, terraformOptions, "is_enabled")
+		require.Equal(t, "true", isEnabledOutput, "The is_enabled output should be true when the module is enabled")
 
-	// Verify repository name output
-	repositoryNameOutput := terraform.Output(t, terraformOptions, "repository_name")
-	require.NotEmpty(t, repositoryNameOutput, "The repository_name output should not be empty")
+		// Verify repository name output
+		repositoryNameOutput := terraform.Output(t, terraformOptions, "repository_name")
+		require.NotEmpty(t, repositoryNameOutput, "The repository_name output should not be empty")
 
-	// Verify domain name output
-	domainNameOutput := terraform.Output(t, terraformOptions, "domain_name")
-	require.NotEmpty(t, domainNameOutput, "The domain_name output should not be empty")
+		// Verify domain name output
+		domainNameOutput := terraform.Output(t, terraformOptions, "domain_name")
+		require.NotEmpty(t, domainNameOutput, "The domain_name output should not be empty")
+	})
 }
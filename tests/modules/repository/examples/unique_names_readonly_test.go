@@ -0,0 +1,40 @@
+//go:build readonly && examples
+
+package examples
+
+import (
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/naming"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/plan"
+)
+
+// TestNamingOnRepositoryExampleWhenVarsOverrideFixture verifies that the names passed with WithVars
+// win over those of the default fixture, so parallel runs of the fixture get distinct resources.
+func TestNamingOnRepositoryExampleWhenVarsOverrideFixture(t *testing.T) {
+	t.Parallel()
+
+	domainName := naming.Name(t, naming.CodeArtifactDomain, "example-basic-domain")
+	repositoryName := naming.Name(t, naming.CodeArtifactRepository, "my-basic-repository-example")
+
+	// Setup terraform options with isolated provider cache and workspace for the fixture
+	terraformOptions := helper.NewTerraformOptions(t, helper.ExampleSource("repository/basic"),
+		helper.WithWorkspaceCopy(),
+		helper.WithVarFiles("fixtures/default.tfvars"),
+		helper.WithVars(map[string]interface{}{
+			"domain_name":     domainName,
+			"repository_name": repositoryName,
+		}),
+	)
+
+	t.Logf("🔍 Terraform Example Directory: %s", terraformOptions.TerraformDir)
+	t.Logf("📝 Using fixture: fixtures/default.tfvars")
+
+	tfPlan := plan.InitAndPlan(t, terraformOptions)
+
+	plan.RequireAfterAttribute(t, tfPlan, "aws_codeartifact_domain.this[0]", "domain", domainName)
+	plan.RequireAfterAttribute(t, tfPlan, "module.this.aws_codeartifact_repository.this[0]", "repository", repositoryName)
+
+	t.Logf("✅ Planned names override the fixture: %s/%s", domainName, repositoryName)
+}
@@ -0,0 +1,29 @@
+//go:build integration && examples && upgrade
+
+package examples
+
+import (
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/harness"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/naming"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/upgrade"
+)
+
+// TestUpgradeOnRepositoryExampleWhenPreviousReleaseIsApplied verifies that the working tree plans no
+// destroy or replacement of the domain and repository applied by the last release of the module.
+func TestUpgradeOnRepositoryExampleWhenPreviousReleaseIsApplied(t *testing.T) {
+	t.Parallel()
+
+	harness.ForEachRegion(t, func(t *testing.T, awsCtx harness.Context) {
+		upgrade.Run(t, upgrade.Config{
+			Module:   "repository",
+			Example:  "repository/basic",
+			VarFiles: []string{"fixtures/default.tfvars"},
+			Vars: map[string]interface{}{
+				"domain_name":     naming.Name(t, naming.CodeArtifactDomain, "upgrade-domain"),
+				"repository_name": naming.Name(t, naming.CodeArtifactRepository, "upgrade-repository"),
+			},
+		}, awsCtx)
+	})
+}
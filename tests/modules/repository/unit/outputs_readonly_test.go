@@ -0,0 +1,20 @@
+//go:build unit && readonly
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/contract"
+)
+
+// TestOutputContractOnRepositoryModuleWhenEnabledOrDisabled verifies that the repository module declares and
+// plans exactly the outputs of its contract, enabled and disabled.
+func TestOutputContractOnRepositoryModuleWhenEnabledOrDisabled(t *testing.T) {
+	t.Parallel()
+
+	contract.Run(t, contract.Repository, map[string]interface{}{
+		"domain_name":     "contract-domain",
+		"repository_name": "contract-repository",
+	})
+}
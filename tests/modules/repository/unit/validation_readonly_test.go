@@ -0,0 +1,57 @@
+//go:build unit && readonly
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/validation"
+)
+
+// TestValidationOnRepositoryModuleWhenVariablesAreInvalid verifies that every validation block of the
+// repository module rejects invalid values with its exact error message.
+func TestValidationOnRepositoryModuleWhenVariablesAreInvalid(t *testing.T) {
+	t.Parallel()
+
+	const externalConnectionMessage = "The external connection name must be a non-empty string matching a known public pattern (e.g., 'public:npmjs', 'public:pypi', etc.) or null."
+
+	validation.Run(t, validation.Matrix{
+		Module: "repository",
+		Vars: map[string]interface{}{
+			"domain_name":     "validation-domain",
+			"repository_name": "validation-repository",
+		},
+		Cases: []validation.Case{
+			{
+				Name:         "empty-name",
+				Variable:     "upstreams",
+				Vars:         map[string]interface{}{"upstreams": []map[string]interface{}{{"repository_name": ""}}},
+				ErrorMessage: "Each upstream object must have a non-empty 'repository_name'.",
+			},
+			{
+				Name:         "unknown-connection",
+				Variable:     "external_connection",
+				Vars:         map[string]interface{}{"external_connection": "public:npm"},
+				ErrorMessage: externalConnectionMessage,
+			},
+			{
+				Name:         "missing-prefix",
+				Variable:     "external_connection",
+				Vars:         map[string]interface{}{"external_connection": "pypi"},
+				ErrorMessage: externalConnectionMessage,
+			},
+			{
+				Name:         "empty",
+				Variable:     "external_connection",
+				Vars:         map[string]interface{}{"external_connection": ""},
+				ErrorMessage: externalConnectionMessage,
+			},
+			{
+				Name:         "malformed-json",
+				Variable:     "repository_policy_document",
+				Vars:         map[string]interface{}{"repository_policy_document": `{"Version": "2012-10-17"`},
+				ErrorMessage: "The repository_policy_document must be a valid JSON string or null.",
+			},
+		},
+	})
+}
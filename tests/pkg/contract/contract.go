@@ -0,0 +1,406 @@
+// Package contract checks the outputs of a module against a declarative contract: the name, type
+// and sensitivity of every output, and the value it takes when the module is disabled. A contract
+// is checked against the declared outputs, the planned output values and `terraform output -json`
+// after apply, so an output added, removed or retyped without updating the contract fails a test.
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/harness"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/plan"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/repo"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/waiter"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+// Type is the kind of value an output holds. Lists, sets and tuples are all List, and maps and
+// objects are all Map, since a module cannot change one for the other without breaking callers.
+type Type string
+
+// Output types.
+const (
+	String Type = "string"
+	Number Type = "number"
+	Bool   Type = "bool"
+	List   Type = "list"
+	Map    Type = "map"
+)
+
+// Disabled is the value an output takes when the module is disabled.
+type Disabled string
+
+// Values of outputs of a disabled module.
+const (
+	// DisabledNull outputs are null.
+	DisabledNull Disabled = "null"
+
+	// DisabledEmpty outputs are an empty list or map.
+	DisabledEmpty Disabled = "empty"
+
+	// DisabledFalse outputs are false, e.g. is_enabled.
+	DisabledFalse Disabled = "false"
+
+	// DisabledSet outputs keep a non-null value, e.g. feature_flags or the tags.
+	DisabledSet Disabled = "set"
+)
+
+// Mode is whether the module is enabled in the configuration a contract is checked against.
+type Mode string
+
+// Modes of the module under test.
+const (
+	ModeEnabled  Mode = "enabled"
+	ModeDisabled Mode = "disabled"
+)
+
+// enabledVariable is the variable that turns every module of the repository on and off.
+const enabledVariable = "is_enabled"
+
+// Output is the contract of one output.
+type Output struct {
+	Name      string
+	Type      Type
+	Sensitive bool
+
+	// Nullable outputs may be null while the module is enabled, e.g. when an optional feature is off.
+	Nullable bool
+
+	// Disabled is the value of the output when the module is disabled.
+	Disabled Disabled
+}
+
+// Contract is the output contract of a module.
+type Contract struct {
+	// Module is the name of the module under modules/.
+	Module string
+
+	// Outputs are the outputs the module declares, every one of them.
+	Outputs []Output
+}
+
+// value is an output value, as planned or applied.
+type value struct {
+	Value     interface{}
+	Type      Type // Empty when the value is null or unknown.
+	Known     bool
+	Sensitive bool
+}
+
+// appliedOutput is an entry of `terraform output -json`.
+type appliedOutput struct {
+	Sensitive bool            `json:"sensitive"`
+	Type      json.RawMessage `json:"type"`
+	Value     interface{}     `json:"value"`
+}
+
+// Run checks the contract against the outputs the module declares, then plans the module enabled
+// and disabled with vars and checks the planned output values, each in its own subtest.
+func Run(t *testing.T, c Contract, vars map[string]interface{}) {
+	t.Helper()
+
+	dirs, err := repo.NewTFSourcesDir()
+	require.NoError(t, err, "Failed to get Terraform sources directory")
+
+	moduleDir := dirs.GetModulesDir(c.Module)
+
+	t.Run("declared", func(t *testing.T) {
+		RequireDeclared(t, c, moduleDir)
+	})
+
+	for _, mode := range []Mode{ModeEnabled, ModeDisabled} {
+		mode := mode
+
+		t.Run(string(mode), func(t *testing.T) {
+			t.Parallel()
+
+			terraformOptions := helper.NewTerraformOptions(t, helper.ModuleSource(moduleDir),
+				harness.Load(t).TerraformOption(),
+				helper.WithWorkspaceCopy(),
+				helper.WithVars(withMode(vars, mode)),
+				helper.WithNoColor(),
+			)
+
+			t.Logf("🔍 Terraform Module Directory: %s", terraformOptions.TerraformDir)
+
+			RequirePlanned(t, c, plan.InitAndPlan(t, terraformOptions), mode)
+		})
+	}
+}
+
+// RunApplied applies the module with vars in the region of awsCtx, checks `terraform output -json`
+// against the contract and destroys the module, waiting until AWS reports its resources deleted.
+func RunApplied(t *testing.T, c Contract, awsCtx harness.Context, vars map[string]interface{}, mode Mode) {
+	t.Helper()
+
+	dirs, err := repo.NewTFSourcesDir()
+	require.NoError(t, err, "Failed to get Terraform sources directory")
+
+	terraformOptions := helper.NewTerraformOptions(t, helper.ModuleSource(dirs.GetModulesDir(c.Module)),
+		awsCtx.TerraformOption(),
+		helper.WithWorkspaceCopy(),
+		helper.WithVars(withMode(vars, mode)),
+		helper.WithNoColor(),
+	)
+
+	// Destroy resources when the test completes and wait until AWS reports them deleted
+	defer waiter.DestroyAndWait(t, terraformOptions, awsCtx.Region)
+
+	t.Logf("🔍 Terraform Module Directory: %s", terraformOptions.TerraformDir)
+
+	helper.Init(t, terraformOptions)
+	terraform.Apply(t, terraformOptions)
+
+	// Verify a second plan is empty, so the configuration has no perpetual diff
+	plan.RequireIdempotent(t, terraformOptions)
+
+	RequireApplied(t, c, terraformOptions, mode)
+}
+
+// RequireDeclared fails the test unless the outputs declared by the .tf files of dir are exactly
+// the outputs of the contract, with the same sensitivity.
+func RequireDeclared(t *testing.T, c Contract, dir string) {
+	t.Helper()
+
+	declared, err := repo.ParseOutputs(dir)
+	require.NoError(t, err, "Failed to parse the outputs of module %s", c.Module)
+
+	names := make([]string, 0, len(declared))
+	for name := range declared {
+		names = append(names, name)
+	}
+
+	require.ElementsMatch(t, c.names(), names, "Outputs of module %s should match its contract", c.Module)
+
+	for _, output := range c.Outputs {
+		require.Equalf(t, output.Sensitive, declared[output.Name].Sensitive,
+			"Sensitivity of output %q of module %s should match its contract", output.Name, c.Module)
+	}
+
+	t.Logf("✅ Module %s declares the %d outputs of its contract", c.Module, len(c.Outputs))
+}
+
+// RequirePlanned fails the test unless the planned output values of p honour the contract. Values
+// only known after apply are not checked beyond their presence.
+func RequirePlanned(t *testing.T, c Contract, p *plan.Plan, mode Mode) {
+	t.Helper()
+
+	values := make(map[string]value, len(p.OutputChanges))
+
+	for name, change := range p.OutputChanges {
+		unknown, _ := change.AfterUnknown.(bool)
+		sensitive, _ := change.AfterSensitive.(bool)
+
+		values[name] = value{
+			Value:     change.After,
+			Type:      typeOfValue(change.After),
+			Known:     !unknown,
+			Sensitive: sensitive,
+		}
+	}
+
+	c.require(t, values, mode, "planned")
+}
+
+// RequireApplied fails the test unless `terraform output -json` honours the contract.
+func RequireApplied(t *testing.T, c Contract, options *terraform.Options, mode Mode) {
+	t.Helper()
+
+	out, err := terraform.OutputJsonE(t, options, "")
+	require.NoError(t, err, "Failed to read the outputs of %s", options.TerraformDir)
+
+	var applied map[string]appliedOutput
+	require.NoError(t, json.Unmarshal([]byte(out), &applied), "Failed to decode the outputs of %s", options.TerraformDir)
+
+	values := make(map[string]value, len(applied))
+
+	for name, output := range applied {
+		typ, err := typeOfConstraint(output.Type)
+		require.NoError(t, err, "Failed to decode the type of output %q", name)
+
+		values[name] = value{Value: output.Value, Type: typ, Known: true, Sensitive: output.Sensitive}
+	}
+
+	c.require(t, values, mode, "applied")
+}
+
+// require checks planned or applied values against the contract. Terraform does not record null
+// outputs, so a missing output is taken as null.
+func (c Contract) require(t *testing.T, values map[string]value, mode Mode, stage string) {
+	t.Helper()
+
+	outputs := make(map[string]Output, len(c.Outputs))
+	for _, output := range c.Outputs {
+		outputs[output.Name] = output
+	}
+
+	for _, name := range sortedKeys(values) {
+		_, ok := outputs[name]
+		require.Truef(t, ok, "Output %q of module %s is %s but missing from its contract", name, c.Module, stage)
+	}
+
+	for _, output := range c.Outputs {
+		v, ok := values[output.Name]
+		if !ok {
+			v = value{Known: true}
+		}
+
+		if v.Known && v.Value != nil {
+			require.Equalf(t, output.Type, v.Type, "Type of %s output %q of module %s should match its contract", stage, output.Name, c.Module)
+			require.Equalf(t, output.Sensitive, v.Sensitive, "Sensitivity of %s output %q of module %s should match its contract", stage, output.Name, c.Module)
+		}
+
+		if mode == ModeEnabled {
+			if !output.Nullable {
+				require.Truef(t, !v.Known || v.Value != nil,
+					"%s output %q of module %s should not be null while the module is enabled", stage, output.Name, c.Module)
+			}
+
+			continue
+		}
+
+		require.Truef(t, v.Known, "%s output %q of module %s should be known while the module is disabled", stage, output.Name, c.Module)
+		require.NoErrorf(t, output.Disabled.Check(v.Value),
+			"%s output %q of module %s should honour its contract while the module is disabled", stage, output.Name, c.Module)
+	}
+
+	t.Logf("✅ Module %s honours its output contract (%s, %s)", c.Module, stage, mode)
+}
+
+// withMode returns a copy of vars that enables or disables the module.
+func withMode(vars map[string]interface{}, mode Mode) map[string]interface{} {
+	modeVars := make(map[string]interface{}, len(vars)+1)
+	for name, value := range vars {
+		modeVars[name] = value
+	}
+
+	modeVars[enabledVariable] = mode == ModeEnabled
+
+	return modeVars
+}
+
+// Output returns the contract of the named output.
+func (c Contract) Output(name string) (Output, bool) {
+	for _, output := range c.Outputs {
+		if output.Name == name {
+			return output, true
+		}
+	}
+
+	return Output{}, false
+}
+
+// names returns the names of the outputs of the contract.
+func (c Contract) names() []string {
+	names := make([]string, len(c.Outputs))
+	for i, output := range c.Outputs {
+		names[i] = output.Name
+	}
+
+	return names
+}
+
+// Check returns an error unless v is the value of an output of a disabled module.
+func (d Disabled) Check(v interface{}) error {
+	switch d {
+	case DisabledNull:
+		if v != nil {
+			return fmt.Errorf("expected null, got %v", v)
+		}
+	case DisabledEmpty:
+		switch typed := v.(type) {
+		case []interface{}:
+			if len(typed) > 0 {
+				return fmt.Errorf("expected an empty list, got %v", v)
+			}
+		case map[string]interface{}:
+			if len(typed) > 0 {
+				return fmt.Errorf("expected an empty map, got %v", v)
+			}
+		default:
+			return fmt.Errorf("expected an empty list or map, got %v", v)
+		}
+	case DisabledFalse:
+		if v != false {
+			return fmt.Errorf("expected false, got %v", v)
+		}
+	case DisabledSet:
+		if v == nil {
+			return fmt.Errorf("expected a value, got null")
+		}
+	default:
+		return fmt.Errorf("unknown disabled value %q", d)
+	}
+
+	return nil
+}
+
+// typeOfValue returns the type of a decoded JSON value, or "" for null.
+func typeOfValue(v interface{}) Type {
+	switch v.(type) {
+	case string:
+		return String
+	case float64:
+		return Number
+	case bool:
+		return Bool
+	case []interface{}:
+		return List
+	case map[string]interface{}:
+		return Map
+	default:
+		return ""
+	}
+}
+
+// typeOfConstraint returns the type of a JSON-encoded Terraform type, as `terraform output -json`
+// reports it: "string", ["list", "string"], ["object", {...}], ...
+func typeOfConstraint(raw json.RawMessage) (Type, error) {
+	var primitive string
+	if err := json.Unmarshal(raw, &primitive); err == nil {
+		switch primitive {
+		case "string", "number", "bool":
+			return Type(primitive), nil
+		case "dynamic":
+			return "", nil
+		default:
+			return "", fmt.Errorf("unknown primitive type %q", primitive)
+		}
+	}
+
+	var complex []json.RawMessage
+	if err := json.Unmarshal(raw, &complex); err != nil || len(complex) == 0 {
+		return "", fmt.Errorf("invalid type %s", raw)
+	}
+
+	var kind string
+	if err := json.Unmarshal(complex[0], &kind); err != nil {
+		return "", fmt.Errorf("invalid type %s", raw)
+	}
+
+	switch kind {
+	case "list", "set", "tuple":
+		return List, nil
+	case "map", "object":
+		return Map, nil
+	default:
+		return "", fmt.Errorf("unknown type kind %q", kind)
+	}
+}
+
+// sortedKeys returns the keys of values in order, so failures are reported deterministically.
+func sortedKeys(values map[string]value) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,128 @@
+package contract
+
+// Default is the output contract of modules/default.
+var Default = Contract{
+	Module: "default",
+	Outputs: []Output{
+		{Name: "is_enabled", Type: Bool, Disabled: DisabledFalse},
+		{Name: "tags_set", Type: Map, Disabled: DisabledSet},
+	},
+}
+
+// Domain is the output contract of modules/domain.
+var Domain = Contract{
+	Module: "domain",
+	Outputs: []Output{
+		{Name: "domain_arn", Type: String, Disabled: DisabledNull},
+		{Name: "domain_name", Type: String, Disabled: DisabledNull},
+		{Name: "domain_owner", Type: String, Disabled: DisabledNull},
+		{Name: "domain_repository_count", Type: Number, Disabled: DisabledNull},
+		{Name: "domain_encryption_key", Type: String, Disabled: DisabledNull},
+		{Name: "domain_created_time", Type: String, Disabled: DisabledNull},
+		{Name: "domain_asset_size_bytes", Type: Number, Disabled: DisabledNull},
+		{Name: "domain_endpoint", Type: String, Disabled: DisabledNull},
+		{Name: "domain_s3_bucket_arn", Type: String, Disabled: DisabledNull},
+		{Name: "is_enabled", Type: Bool, Disabled: DisabledFalse},
+	},
+}
+
+// DomainPermissions is the output contract of modules/domain-permissions.
+var DomainPermissions = Contract{
+	Module: "domain-permissions",
+	Outputs: []Output{
+		{Name: "policy_revision", Type: String, Disabled: DisabledNull},
+		{Name: "resource_arn", Type: String, Disabled: DisabledNull},
+		{Name: "policy_document", Type: String, Disabled: DisabledNull},
+		{Name: "domain_name", Type: String, Disabled: DisabledNull},
+		{Name: "domain_owner", Type: String, Disabled: DisabledNull},
+		{Name: "feature_flags", Type: Map, Disabled: DisabledSet},
+		{Name: "is_enabled", Type: Bool, Disabled: DisabledFalse},
+	},
+}
+
+// DomainPermissionsCrossAccount is the output contract of modules/domain-permissions-cross-account.
+var DomainPermissionsCrossAccount = Contract{
+	Module: "domain-permissions-cross-account",
+	Outputs: []Output{
+		{Name: "cross_account_role_arn", Type: String, Disabled: DisabledNull},
+		{Name: "cross_account_role_name", Type: String, Disabled: DisabledNull},
+		{Name: "cross_account_role_id", Type: String, Disabled: DisabledNull},
+		{Name: "cross_account_role_unique_id", Type: String, Disabled: DisabledNull},
+		{Name: "policy_arns", Type: List, Disabled: DisabledEmpty},
+		{Name: "feature_flags", Type: Map, Disabled: DisabledSet},
+		{Name: "module_enabled", Type: Bool, Disabled: DisabledFalse},
+	},
+}
+
+// Foundation is the output contract of modules/foundation. Every resource output is nullable, since
+// each component can be turned off on its own.
+var Foundation = Contract{
+	Module: "foundation",
+	Outputs: []Output{
+		{Name: "kms_key_arn", Type: String, Nullable: true, Disabled: DisabledNull},
+		{Name: "kms_key_id", Type: String, Nullable: true, Disabled: DisabledNull},
+		{Name: "kms_key_alias_arn", Type: String, Nullable: true, Disabled: DisabledNull},
+		{Name: "kms_key_alias_name", Type: String, Nullable: true, Disabled: DisabledNull},
+		{Name: "log_group_arn", Type: String, Nullable: true, Disabled: DisabledNull},
+		{Name: "log_group_name", Type: String, Nullable: true, Disabled: DisabledNull},
+		{Name: "s3_bucket_id", Type: String, Nullable: true, Disabled: DisabledNull},
+		{Name: "s3_bucket_arn", Type: String, Nullable: true, Disabled: DisabledNull},
+		{Name: "s3_bucket_domain_name", Type: String, Nullable: true, Disabled: DisabledNull},
+		{Name: "s3_bucket_regional_domain_name", Type: String, Nullable: true, Disabled: DisabledNull},
+		{Name: "oidc_provider_arn", Type: String, Nullable: true, Disabled: DisabledNull},
+		{Name: "oidc_role_arns", Type: Map, Disabled: DisabledEmpty},
+		{Name: "oidc_role_names", Type: Map, Disabled: DisabledEmpty},
+		{Name: "feature_flags", Type: Map, Disabled: DisabledSet},
+		{Name: "tags_set", Type: Map, Disabled: DisabledSet},
+		{Name: "is_enabled", Type: Bool, Disabled: DisabledFalse},
+	},
+}
+
+// Repository is the output contract of modules/repository.
+var Repository = Contract{
+	Module: "repository",
+	Outputs: []Output{
+		{Name: "repository_arn", Type: String, Disabled: DisabledNull},
+		{Name: "repository_name", Type: String, Disabled: DisabledNull},
+		{Name: "repository_administrator_account", Type: String, Disabled: DisabledNull},
+		{Name: "repository_domain_owner", Type: String, Disabled: DisabledNull},
+		{Name: "policy_revision", Type: String, Sensitive: true, Nullable: true, Disabled: DisabledNull},
+		{Name: "is_enabled", Type: Bool, Disabled: DisabledFalse},
+	},
+}
+
+// RepositoryPermissions is the output contract of modules/repository-permissions.
+var RepositoryPermissions = Contract{
+	Module: "repository-permissions",
+	Outputs: []Output{
+		{Name: "policy_revision", Type: String, Disabled: DisabledNull},
+		{Name: "resource_arn", Type: String, Disabled: DisabledNull},
+		{Name: "policy_document", Type: String, Sensitive: true, Disabled: DisabledNull},
+		{Name: "domain_name", Type: String, Disabled: DisabledNull},
+		{Name: "repository_name", Type: String, Disabled: DisabledNull},
+		{Name: "domain_owner", Type: String, Disabled: DisabledNull},
+		{Name: "is_enabled", Type: Bool, Disabled: DisabledFalse},
+	},
+}
+
+// All holds the contract of every module.
+var All = []Contract{
+	Default,
+	Domain,
+	DomainPermissions,
+	DomainPermissionsCrossAccount,
+	Foundation,
+	Repository,
+	RepositoryPermissions,
+}
+
+// ForModule returns the contract of the named module.
+func ForModule(module string) (Contract, bool) {
+	for _, c := range All {
+		if c.Module == module {
+			return c, true
+		}
+	}
+
+	return Contract{}, false
+}
@@ -0,0 +1,345 @@
+// Package golden compares normalized Terraform plans with the snapshots committed under
+// tests/modules/<module>/golden/, so an unintended plan change fails with a readable diff.
+package golden
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/naming"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/plan"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/repo"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/stretchr/testify/require"
+)
+
+// update rewrites the golden files with the current plans, e.g. `go test -tags ... ./modules/domain/... -update`.
+var update = flag.Bool("update", false, "rewrite the golden plan snapshots instead of comparing the plans against them")
+
+// Placeholders written in place of the values that vary between accounts, regions and runs.
+const (
+	unknownValue         = "(known after apply)"
+	sensitiveValue       = "(sensitive value)"
+	accountIDPlaceholder = "<account-id>"
+	regionPlaceholder    = "<region>"
+	timestampPlaceholder = "<timestamp>"
+	suffixPlaceholder    = "-<suffix>"
+)
+
+const (
+	// regionVariable is the input variable through which the examples receive the AWS region.
+	regionVariable = "aws_region"
+
+	// callerIdentityType is the data source the examples and modules read the account ID from.
+	callerIdentityType = "aws_caller_identity"
+
+	// diffContext is the number of unchanged lines shown around each difference.
+	diffContext = 3
+)
+
+// timestampPattern matches RFC 3339 timestamps, such as the expiry tag of pkg/helper.
+var timestampPattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})`)
+
+// runTagKeys are the tags of pkg/helper, which differ between every test and run.
+var runTagKeys = map[string]bool{
+	helper.RunIDTagKey:     true,
+	helper.TestTagKey:      true,
+	helper.GitSHATagKey:    true,
+	helper.ExpiresAtTagKey: true,
+}
+
+// snapshot is the content of a golden file.
+type snapshot struct {
+	Resources []resourceSnapshot     `json:"resources"`
+	Outputs   map[string]interface{} `json:"outputs"`
+}
+
+// resourceSnapshot is the planned change of a resource, its unknown and sensitive values replaced.
+type resourceSnapshot struct {
+	Address string        `json:"address"`
+	Actions []plan.Action `json:"actions"`
+	After   interface{}   `json:"after"`
+}
+
+// replacement substitutes a placeholder for every match of a pattern.
+type replacement struct {
+	pattern *regexp.Regexp
+	with    string
+}
+
+// RequirePlan compares the normalized plan of an example/fixture recipe with its golden file,
+// tests/modules/<module>/golden/<example>/<fixture>.json, or rewrites the file when -update is set.
+func RequirePlan(t *testing.T, example repo.Example, fixtureName string, p *plan.Plan) {
+	t.Helper()
+
+	dirs, err := repo.NewTFSourcesDir()
+	require.NoError(t, err, "Failed to get Terraform sources directory")
+
+	got, err := Normalize(p)
+	require.NoError(t, err, "Failed to normalize the plan of %s/%s", example.Path, fixtureName)
+
+	Require(t, filepath.Join(dirs.GetGoldenDir(example.Module), example.Name, fixtureName+".json"), got)
+}
+
+// Require compares got with the golden file at path and fails the test with a unified diff when
+// they differ. With -update, the file is written instead.
+func Require(t *testing.T, path string, got []byte) {
+	t.Helper()
+
+	name := displayPath(path)
+
+	if *update {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755), "Failed to create the directory of golden file %s", name)
+		require.NoError(t, os.WriteFile(path, got, 0o644), "Failed to write golden file %s", name)
+		t.Logf("📝 Updated golden file %s", name)
+
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		t.Fatalf("❌ Golden file %s does not exist; run the test with -update to record it", name)
+	}
+
+	require.NoError(t, err, "Failed to read golden file %s", name)
+
+	if bytes.Equal(want, got) {
+		t.Logf("✅ Plan matches golden file %s", name)
+		return
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(want)),
+		B:        difflib.SplitLines(string(got)),
+		FromFile: name,
+		ToFile:   "plan",
+		Context:  diffContext,
+	})
+	require.NoError(t, err, "Failed to diff the plan against golden file %s", name)
+
+	t.Fatalf("❌ Plan differs from golden file %s; run the test with -update if the change is intended:\n%s", name, diff)
+}
+
+// Normalize renders the resource and output changes of a plan as indented JSON, sorted by address.
+// Values only known after apply and sensitive values are replaced with a marker, the run tags of
+// pkg/helper are removed, and the account ID, region, timestamps and pkg/naming suffixes of the run
+// are replaced with placeholders, so the result only changes when the planned infrastructure does.
+func Normalize(p *plan.Plan) ([]byte, error) {
+	replacements := volatileValues(p)
+
+	doc := snapshot{
+		Resources: make([]resourceSnapshot, 0, len(p.ResourceChanges)),
+		Outputs:   make(map[string]interface{}, len(p.OutputChanges)),
+	}
+
+	for _, rc := range p.ResourceChanges {
+		doc.Resources = append(doc.Resources, resourceSnapshot{
+			Address: rc.Address,
+			Actions: rc.Change.Actions,
+			After:   scrub(merge(rc.Change.After, rc.Change.AfterUnknown, rc.Change.AfterSensitive), replacements),
+		})
+	}
+
+	sort.Slice(doc.Resources, func(i, j int) bool {
+		return doc.Resources[i].Address < doc.Resources[j].Address
+	})
+
+	for name, change := range p.OutputChanges {
+		doc.Outputs[name] = scrub(merge(change.After, change.AfterUnknown, change.AfterSensitive), replacements)
+	}
+
+	var buf bytes.Buffer
+
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(doc); err != nil {
+		return nil, fmt.Errorf("failed to encode normalized plan: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// volatileValues returns the replacements of the values that depend on where and when the plan ran.
+func volatileValues(p *plan.Plan) []replacement {
+	replacements := []replacement{{pattern: timestampPattern, with: timestampPlaceholder}}
+
+	if p.PriorState != nil {
+		for _, accountID := range callerAccountIDs(p.PriorState.Values.RootModule) {
+			replacements = append(replacements, replacement{
+				pattern: regexp.MustCompile(regexp.QuoteMeta(accountID)),
+				with:    accountIDPlaceholder,
+			})
+		}
+	}
+
+	if region, ok := p.Variables[regionVariable].Value.(string); ok && region != "" {
+		replacements = append(replacements, replacement{
+			pattern: regexp.MustCompile(`\b` + regexp.QuoteMeta(region) + `\b`),
+			with:    regionPlaceholder,
+		})
+	}
+
+	if namer, err := naming.DefaultE(); err == nil {
+		replacements = append(replacements, replacement{
+			pattern: regexp.MustCompile(`-` + regexp.QuoteMeta(namer.RunID()) + `[0-9a-z]{6}`),
+			with:    suffixPlaceholder,
+		})
+	}
+
+	return replacements
+}
+
+// callerAccountIDs returns the account IDs read by the aws_caller_identity data sources of a module
+// and its children.
+func callerAccountIDs(module plan.Module) []string {
+	var ids []string
+
+	for _, resource := range module.Resources {
+		if resource.Mode != plan.ModeData || resource.Type != callerIdentityType {
+			continue
+		}
+
+		if id, ok := resource.Values["account_id"].(string); ok && id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	for _, child := range module.ChildModules {
+		ids = append(ids, callerAccountIDs(child)...)
+	}
+
+	return ids
+}
+
+// merge combines a planned value with its after_unknown and after_sensitive counterparts, which
+// mirror its structure with true at the unknown or sensitive paths.
+func merge(after, unknown, sensitive interface{}) interface{} {
+	if b, ok := unknown.(bool); ok && b {
+		return unknownValue
+	}
+
+	if b, ok := sensitive.(bool); ok && b {
+		return sensitiveValue
+	}
+
+	// Attributes only known after apply may be missing from the planned value altogether
+	if after == nil {
+		switch unknown.(type) {
+		case map[string]interface{}:
+			after = map[string]interface{}{}
+		case []interface{}:
+			after = []interface{}{}
+		}
+	}
+
+	switch v := after.(type) {
+	case map[string]interface{}:
+		merged := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			merged[key] = merge(item, field(unknown, key), field(sensitive, key))
+		}
+
+		if u, ok := unknown.(map[string]interface{}); ok {
+			for key, item := range u {
+				if _, ok := merged[key]; !ok {
+					merged[key] = merge(nil, item, field(sensitive, key))
+				}
+			}
+		}
+
+		return merged
+	case []interface{}:
+		length := len(v)
+		if u, ok := unknown.([]interface{}); ok && len(u) > length {
+			length = len(u)
+		}
+
+		merged := make([]interface{}, length)
+		for i := range merged {
+			var item interface{}
+			if i < len(v) {
+				item = v[i]
+			}
+
+			merged[i] = merge(item, element(unknown, i), element(sensitive, i))
+		}
+
+		return merged
+	default:
+		return after
+	}
+}
+
+// scrub removes the run tags from every map and applies the replacements to every string.
+func scrub(value interface{}, replacements []replacement) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if runTagKeys[key] {
+				delete(v, key)
+				continue
+			}
+
+			v[key] = scrub(item, replacements)
+		}
+
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = scrub(item, replacements)
+		}
+
+		return v
+	case string:
+		for _, r := range replacements {
+			v = r.pattern.ReplaceAllString(v, r.with)
+		}
+
+		return v
+	default:
+		return value
+	}
+}
+
+// field returns the member of a decoded JSON object, or nil.
+func field(value interface{}, key string) interface{} {
+	if m, ok := value.(map[string]interface{}); ok {
+		return m[key]
+	}
+
+	return nil
+}
+
+// element returns the element of a decoded JSON list, or nil.
+func element(value interface{}, i int) interface{} {
+	if l, ok := value.([]interface{}); ok && i < len(l) {
+		return l[i]
+	}
+
+	return nil
+}
+
+// displayPath returns path relative to the repository root when it is inside it.
+func displayPath(path string) string {
+	dirs, err := repo.NewTFSourcesDir()
+	if err != nil {
+		return path
+	}
+
+	if rel, err := filepath.Rel(dirs.GetRootDir(), path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+
+	return path
+}
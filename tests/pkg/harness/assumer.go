@@ -0,0 +1,84 @@
+package harness
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+// Assumer is the second principal of a test run: credentials other than the ones Terraform runs
+// with, used to assume a role created by a module the way an external account would.
+type Assumer struct {
+	// Config is the AWS SDK configuration of the principal in the context's region.
+	Config aws.Config
+
+	// PrincipalARN is the IAM ARN a trust policy names for the principal.
+	PrincipalARN string
+}
+
+// assumerSettings are the configured profile and principal ARN of the second principal.
+type assumerSettings struct {
+	profile      string
+	principalARN string
+}
+
+// Assumer loads the second principal from the shared config profile named by
+// TF_TEST_AWS_ASSUMER_PROFILE, talking to the same endpoint as the Terraform run described by
+// options. Its ARN is TF_TEST_AWS_ASSUMER_PRINCIPAL_ARN, or is derived from its caller identity. The
+// test is skipped when no profile is configured.
+func (c Context) Assumer(t *testing.T, options *terraform.Options) Assumer {
+	t.Helper()
+
+	if c.assumer.profile == "" {
+		t.Skipf("⏭️ No second principal configured; set %s to a profile that can call sts:AssumeRole", AssumerProfileEnvVar)
+	}
+
+	cfg, err := helper.LoadAWSConfig(context.Background(), options, c.Region, config.WithSharedConfigProfile(c.assumer.profile))
+	require.NoError(t, err, "Failed to load AWS configuration of profile %s", c.assumer.profile)
+
+	principalARN := c.assumer.principalARN
+	if principalARN == "" {
+		out, err := sts.NewFromConfig(cfg).GetCallerIdentity(context.Background(), &sts.GetCallerIdentityInput{})
+		require.NoError(t, err, "Failed to resolve the caller identity of profile %s", c.assumer.profile)
+
+		principalARN, err = trustedPrincipalARN(aws.ToString(out.Arn))
+		require.NoError(t, err, "Failed to derive the principal ARN of profile %s", c.assumer.profile)
+	}
+
+	t.Logf("👤 Second principal: %s (profile %s)", principalARN, c.assumer.profile)
+
+	return Assumer{Config: cfg, PrincipalARN: principalARN}
+}
+
+// trustedPrincipalARN returns the ARN a trust policy names for a caller: IAM users and roles as
+// they are, and the role of an assumed-role session. STS does not report the path of that role, so
+// roles with a path need TF_TEST_AWS_ASSUMER_PRINCIPAL_ARN.
+func trustedPrincipalARN(callerARN string) (string, error) {
+	parsed, err := arn.Parse(callerARN)
+	if err != nil {
+		return "", fmt.Errorf("caller ARN %q: %w", callerARN, err)
+	}
+
+	if parsed.Service == "iam" {
+		return callerARN, nil
+	}
+
+	parts := strings.Split(parsed.Resource, "/")
+	if parsed.Service != "sts" || parts[0] != "assumed-role" || len(parts) < 3 {
+		return "", fmt.Errorf("caller %s is neither an IAM principal nor an assumed-role session; set %s", callerARN, AssumerPrincipalARNEnvVar)
+	}
+
+	parsed.Service = "iam"
+	parsed.Resource = "role/" + parts[1]
+
+	return parsed.String(), nil
+}
@@ -0,0 +1,309 @@
+package harness
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/repo"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+// Environment variables that configure the test context. They take precedence over the config file.
+const (
+	// ConfigFileEnvVar points at the JSON config file; defaults to tests/test-config.json when it exists.
+	ConfigFileEnvVar = "TF_TEST_CONFIG_FILE"
+
+	// RegionsEnvVar lists the regions to run the suites in, comma-separated, e.g. "us-west-2,eu-west-1".
+	RegionsEnvVar = "TF_TEST_AWS_REGIONS"
+
+	// AccountIDEnvVar sets the AWS account ID instead of resolving it through STS.
+	AccountIDEnvVar = "TF_TEST_AWS_ACCOUNT_ID"
+
+	// PartitionEnvVar sets the AWS partition instead of deriving it from the region.
+	PartitionEnvVar = "TF_TEST_AWS_PARTITION"
+
+	// AssumerProfileEnvVar names the shared config profile of a second principal, used by tests
+	// that assume a role created by a module from outside of it.
+	AssumerProfileEnvVar = "TF_TEST_AWS_ASSUMER_PROFILE"
+
+	// AssumerPrincipalARNEnvVar sets the IAM ARN to trust for the second principal instead of
+	// deriving it from its caller identity, e.g. for a role with a path.
+	AssumerPrincipalARNEnvVar = "TF_TEST_AWS_ASSUMER_PRINCIPAL_ARN"
+)
+
+const (
+	// defaultConfigFile is the config file read when TF_TEST_CONFIG_FILE is not set, relative to tests/.
+	defaultConfigFile = "test-config.json"
+
+	// defaultRegion matches the aws_region default of the examples.
+	defaultRegion = "us-west-2"
+)
+
+// Config is the content of the test config file.
+type Config struct {
+	Regions             []string `json:"regions"`
+	AccountID           string   `json:"account_id"`
+	Partition           string   `json:"partition"`
+	AssumerProfile      string   `json:"assumer_profile"`
+	AssumerPrincipalARN string   `json:"assumer_principal_arn"`
+}
+
+// Context is the AWS region, account and partition a test runs against. Terraform options and
+// SDK clients built from the same Context always talk to the same region.
+type Context struct {
+	Region    string
+	Partition string
+
+	accountID string
+	assumer   assumerSettings
+}
+
+var (
+	settingsOnce sync.Once
+	settings     Config
+	settingsErr  error
+
+	// accounts caches the resolved account IDs by AWS endpoint override, "" being AWS itself.
+	accountsMu sync.Mutex
+	accounts   = map[string]string{}
+)
+
+// Load returns the context of the first configured region. Use ForEachRegion to run a test in
+// every configured region instead.
+func Load(t *testing.T) Context {
+	return Contexts(t)[0]
+}
+
+// Contexts returns one context per configured region, in the configured order.
+func Contexts(t *testing.T) []Context {
+	cfg := loadSettings(t)
+
+	contexts := make([]Context, 0, len(cfg.Regions))
+	for _, region := range cfg.Regions {
+		partition := cfg.Partition
+		if partition == "" {
+			partition = partitionForRegion(region)
+		}
+
+		contexts = append(contexts, Context{
+			Region:    region,
+			Partition: partition,
+			accountID: cfg.AccountID,
+			assumer:   assumerSettings{profile: cfg.AssumerProfile, principalARN: cfg.AssumerPrincipalARN},
+		})
+	}
+
+	return contexts
+}
+
+// ForEachRegion runs fn in a subtest named after each configured region. The regions run one after
+// the other because S3 bucket and IAM role names are global: parallel regions would collide.
+func ForEachRegion(t *testing.T, fn func(t *testing.T, awsCtx Context)) {
+	for _, awsCtx := range Contexts(t) {
+		t.Run(awsCtx.Region, func(t *testing.T) {
+			t.Logf("🌍 Running in region %s (partition %s)", awsCtx.Region, awsCtx.Partition)
+			fn(t, awsCtx)
+		})
+	}
+}
+
+// TerraformOption passes the context's region to Terraform, as the aws_region variable and as
+// AWS_REGION/AWS_DEFAULT_REGION.
+func (c Context) TerraformOption() helper.SetupOption {
+	return helper.WithRegion(c.Region)
+}
+
+// AWSConfig loads the AWS SDK configuration for the context's region, talking to the same endpoint
+// as the Terraform run described by options.
+func (c Context) AWSConfig(t *testing.T, options *terraform.Options) aws.Config {
+	cfg, err := helper.LoadAWSConfig(context.Background(), options, c.Region)
+	require.NoError(t, err, "Failed to load AWS configuration for region %s", c.Region)
+
+	return cfg
+}
+
+// AccountID returns the configured AWS account ID, or resolves it through sts:GetCallerIdentity
+// against the same endpoint as the Terraform run described by options, once per process and
+// endpoint. The caller's partition is checked against the context's on the way.
+func (c Context) AccountID(t *testing.T, options *terraform.Options) string {
+	if c.accountID != "" {
+		return c.accountID
+	}
+
+	cfg := c.AWSConfig(t, options)
+	endpoint := aws.ToString(cfg.BaseEndpoint)
+
+	accountsMu.Lock()
+	defer accountsMu.Unlock()
+
+	if id, ok := accounts[endpoint]; ok {
+		return id
+	}
+
+	id, err := c.resolveAccountID(cfg)
+	require.NoError(t, err, "Failed to resolve AWS account ID")
+
+	accounts[endpoint] = id
+
+	return id
+}
+
+// resolveAccountID asks STS for the account of the credentials in cfg.
+func (c Context) resolveAccountID(cfg aws.Config) (string, error) {
+	out, err := sts.NewFromConfig(cfg).GetCallerIdentity(context.Background(), &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve the AWS account ID: %w", err)
+	}
+
+	callerARN, err := arn.Parse(aws.ToString(out.Arn))
+	if err == nil && callerARN.Partition != c.Partition {
+		return "", fmt.Errorf("caller %s is in partition %s, not %s", callerARN, callerARN.Partition, c.Partition)
+	}
+
+	return aws.ToString(out.Account), nil
+}
+
+// ARN builds an ARN in the context's partition, region and account, the account being the one of
+// the Terraform run described by options.
+func (c Context) ARN(t *testing.T, options *terraform.Options, service, resource string) string {
+	return arn.ARN{
+		Partition: c.Partition,
+		Service:   service,
+		Region:    c.Region,
+		AccountID: c.AccountID(t, options),
+		Resource:  resource,
+	}.String()
+}
+
+// LoadConfig resolves the config file and environment once per process, for callers outside
+// tests such as cmd/sweeper.
+func LoadConfig() (Config, error) {
+	settingsOnce.Do(func() {
+		settings, settingsErr = resolveSettings()
+	})
+
+	return settings, settingsErr
+}
+
+// loadSettings resolves the config file and environment once per process.
+func loadSettings(t *testing.T) Config {
+	cfg, err := LoadConfig()
+	require.NoError(t, err, "Failed to resolve the AWS test context")
+
+	return cfg
+}
+
+// resolveSettings merges the config file with the environment. Regions come from
+// TF_TEST_AWS_REGIONS, then the config file, then AWS_REGION/AWS_DEFAULT_REGION, then us-west-2.
+func resolveSettings() (Config, error) {
+	cfg, err := readConfigFile()
+	if err != nil {
+		return Config{}, err
+	}
+
+	if regions := splitList(os.Getenv(RegionsEnvVar)); len(regions) > 0 {
+		cfg.Regions = regions
+	}
+
+	if len(cfg.Regions) == 0 {
+		for _, name := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+			if region := os.Getenv(name); region != "" {
+				cfg.Regions = []string{region}
+				break
+			}
+		}
+	}
+
+	if len(cfg.Regions) == 0 {
+		cfg.Regions = []string{defaultRegion}
+	}
+
+	if accountID := os.Getenv(AccountIDEnvVar); accountID != "" {
+		cfg.AccountID = accountID
+	}
+
+	if partition := os.Getenv(PartitionEnvVar); partition != "" {
+		cfg.Partition = partition
+	}
+
+	if profile := os.Getenv(AssumerProfileEnvVar); profile != "" {
+		cfg.AssumerProfile = profile
+	}
+
+	if principalARN := os.Getenv(AssumerPrincipalARNEnvVar); principalARN != "" {
+		cfg.AssumerPrincipalARN = principalARN
+	}
+
+	return cfg, nil
+}
+
+// readConfigFile reads the config file named by TF_TEST_CONFIG_FILE, or tests/test-config.json when
+// present. A missing default file yields an empty configuration.
+func readConfigFile() (Config, error) {
+	path := os.Getenv(ConfigFileEnvVar)
+	explicit := path != ""
+
+	if !explicit {
+		dirs, err := repo.NewTFSourcesDir()
+		if err != nil {
+			return Config{}, fmt.Errorf("failed to get Terraform sources directory: %w", err)
+		}
+
+		path = filepath.Join(dirs.GetRootDir(), "tests", defaultConfigFile)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && os.IsNotExist(err) {
+			return Config{}, nil
+		}
+
+		return Config{}, fmt.Errorf("failed to read test config file %s: %w", path, err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(content, &cfg); err != nil {
+		return Config{}, fmt.Errorf("failed to decode test config file %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// partitionForRegion derives the AWS partition from a region name.
+func partitionForRegion(region string) string {
+	switch {
+	case strings.HasPrefix(region, "cn-"):
+		return "aws-cn"
+	case strings.HasPrefix(region, "us-gov-"):
+		return "aws-us-gov"
+	case strings.HasPrefix(region, "us-iso-"):
+		return "aws-iso"
+	case strings.HasPrefix(region, "us-isob-"):
+		return "aws-iso-b"
+	default:
+		return "aws"
+	}
+}
+
+// splitList splits a comma-separated list, dropping blank entries.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
@@ -0,0 +1,276 @@
+package helper
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	// AWSEndpointEnvVar enables the endpoint override for every test when set to the URL of a local AWS emulator.
+	AWSEndpointEnvVar = "TF_TEST_AWS_ENDPOINT_URL"
+
+	// Environment variables understood by both the Terraform AWS provider and the AWS SDK.
+	awsEndpointURLEnvVar     = "AWS_ENDPOINT_URL"
+	awsAccessKeyIDEnvVar     = "AWS_ACCESS_KEY_ID"
+	awsSecretAccessKeyEnvVar = "AWS_SECRET_ACCESS_KEY"
+
+	// Credentials accepted by local AWS emulators.
+	defaultEmulatorCredential = "test"
+
+	// defaultEmulatorRegion is used when the endpoint configuration names no region and the
+	// configuration declares no AWS provider of its own.
+	defaultEmulatorRegion = "us-east-1"
+
+	// Files generated in the workspace to point the AWS provider at the emulator.
+	awsEndpointsOverrideFile = "zz_aws_endpoints_override.tf"
+	awsEndpointsProviderFile = "zz_aws_endpoints.tf"
+)
+
+// awsEndpointServices lists the Terraform AWS provider `endpoints` entries used by the modules and the tests.
+var awsEndpointServices = []string{"codeartifact", "iam", "kms", "logs", "s3", "sts"}
+
+// EndpointConfig points Terraform and the AWS SDK at a local AWS emulator (LocalStack, moto, ...)
+// instead of a real account.
+type EndpointConfig struct {
+	URL             string // The emulator endpoint, e.g. "http://localhost:4566".
+	Region          string // The region declared when the configuration has no AWS provider block of its own.
+	AccessKeyID     string // The access key presented to the emulator; defaults to "test".
+	SecretAccessKey string // The secret key presented to the emulator; defaults to "test".
+}
+
+// WithAWSEndpoints points the AWS provider at the emulator described by endpoints. Since the provider
+// overrides are written as files, the Terraform directory is copied into a workspace first.
+// Setting TF_TEST_AWS_ENDPOINT_URL enables the override for every test without this option.
+func WithAWSEndpoints(endpoints EndpointConfig) SetupOption {
+	return func(c *setupConfig) error {
+		if err := endpoints.validate(); err != nil {
+			return err
+		}
+
+		c.awsEndpoints = &endpoints
+
+		return nil
+	}
+}
+
+// validate checks that the endpoint is an absolute HTTP(S) URL.
+func (e EndpointConfig) validate() error {
+	u, err := url.Parse(e.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("AWS endpoint %q must be an absolute http(s) URL", e.URL)
+	}
+
+	return nil
+}
+
+// endpointConfigFromEnv returns the endpoint configuration enabled through TF_TEST_AWS_ENDPOINT_URL, if any.
+func endpointConfigFromEnv() (*EndpointConfig, error) {
+	endpoint := os.Getenv(AWSEndpointEnvVar)
+	if endpoint == "" {
+		return nil, nil
+	}
+
+	endpoints := &EndpointConfig{URL: endpoint}
+	if err := endpoints.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", AWSEndpointEnvVar, err)
+	}
+
+	return endpoints, nil
+}
+
+// withDefaults returns a copy of the configuration with the emulator credentials and region filled in.
+func (e EndpointConfig) withDefaults() EndpointConfig {
+	if e.AccessKeyID == "" {
+		e.AccessKeyID = defaultEmulatorCredential
+	}
+
+	if e.SecretAccessKey == "" {
+		e.SecretAccessKey = defaultEmulatorCredential
+	}
+
+	if e.Region == "" {
+		e.Region = defaultEmulatorRegion
+	}
+
+	return e
+}
+
+// awsEndpointEnv returns the environment variables pointing Terraform at the emulator selected by cfg,
+// or nil when no emulator is configured.
+func awsEndpointEnv(t *testing.T, cfg *setupConfig) map[string]string {
+	if cfg.awsEndpoints == nil {
+		return nil
+	}
+
+	endpoints := cfg.awsEndpoints.withDefaults()
+
+	t.Logf("🧪 Using AWS endpoint override at: %s", endpoints.URL)
+
+	return map[string]string{
+		awsEndpointURLEnvVar:     endpoints.URL,
+		awsAccessKeyIDEnvVar:     endpoints.AccessKeyID,
+		awsSecretAccessKeyEnvVar: endpoints.SecretAccessKey,
+	}
+}
+
+// WriteAWSEndpointOverrides writes Terraform files into terraformDir that point every AWS provider
+// configuration at the emulator. Existing `provider "aws"` blocks, aliased ones included, are
+// extended through an override file; when the directory declares no default AWS provider, one is
+// added in the emulator's region. Never call it on a directory of the repository itself.
+func WriteAWSEndpointOverrides(terraformDir string, endpoints EndpointConfig) error {
+	endpoints = endpoints.withDefaults()
+
+	aliases, hasDefault, err := awsProviderAliases(terraformDir)
+	if err != nil {
+		return err
+	}
+
+	var overrides strings.Builder
+	for _, alias := range aliases {
+		overrides.WriteString(renderAWSProviderEndpoints(endpoints, alias, ""))
+	}
+
+	if hasDefault {
+		overrides.WriteString(renderAWSProviderEndpoints(endpoints, "", ""))
+	} else {
+		provider := renderAWSProviderEndpoints(endpoints, "", endpoints.Region)
+		if err := os.WriteFile(filepath.Join(terraformDir, awsEndpointsProviderFile), []byte(provider), 0o600); err != nil {
+			return fmt.Errorf("failed to write AWS endpoint provider in %s: %w", terraformDir, err)
+		}
+	}
+
+	if overrides.Len() == 0 {
+		return nil
+	}
+
+	if err := os.WriteFile(filepath.Join(terraformDir, awsEndpointsOverrideFile), []byte(overrides.String()), 0o600); err != nil {
+		return fmt.Errorf("failed to write AWS endpoint overrides in %s: %w", terraformDir, err)
+	}
+
+	return nil
+}
+
+// renderAWSProviderEndpoints renders a provider "aws" block pointing every service at the emulator.
+// The region is only rendered for a provider block that does not override an existing one.
+func renderAWSProviderEndpoints(endpoints EndpointConfig, alias, region string) string {
+	var b strings.Builder
+
+	b.WriteString("provider \"aws\" {\n")
+
+	if alias != "" {
+		fmt.Fprintf(&b, "  alias                       = %q\n", alias)
+	}
+
+	if region != "" {
+		fmt.Fprintf(&b, "  region                      = %q\n", region)
+	}
+
+	b.WriteString("  skip_credentials_validation = true\n")
+	b.WriteString("  skip_metadata_api_check     = true\n")
+	b.WriteString("  s3_use_path_style           = true\n\n")
+	b.WriteString("  endpoints {\n")
+
+	for _, service := range awsEndpointServices {
+		fmt.Fprintf(&b, "    %-12s = %q\n", service, endpoints.URL)
+	}
+
+	b.WriteString("  }\n}\n\n")
+
+	return b.String()
+}
+
+// awsProviderAliases returns the aliases of the provider "aws" blocks declared in the .tf files of dir,
+// and whether a default (non-aliased) AWS provider is declared.
+func awsProviderAliases(dir string) (aliases []string, hasDefault bool, err error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list Terraform files in %s: %w", dir, err)
+	}
+
+	parser := hclparse.NewParser()
+
+	for _, file := range files {
+		if strings.HasSuffix(file, "_override.tf") {
+			continue
+		}
+
+		parsed, diags := parser.ParseHCLFile(file)
+		if diags.HasErrors() {
+			return nil, false, fmt.Errorf("failed to parse %s: %s", file, diags.Error())
+		}
+
+		body, ok := parsed.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+
+		for _, block := range body.Blocks {
+			if block.Type != "provider" || len(block.Labels) != 1 || block.Labels[0] != "aws" {
+				continue
+			}
+
+			attr, ok := block.Body.Attributes["alias"]
+			if !ok {
+				hasDefault = true
+				continue
+			}
+
+			value, diags := attr.Expr.Value(&hcl.EvalContext{})
+			if diags.HasErrors() || value.Type() != cty.String {
+				return nil, false, fmt.Errorf("provider alias in %s must be a literal string", file)
+			}
+
+			aliases = append(aliases, value.AsString())
+		}
+	}
+
+	sort.Strings(aliases)
+
+	return aliases, hasDefault, nil
+}
+
+// LoadAWSConfig loads the AWS SDK configuration for the given region, talking to the same endpoint
+// as the Terraform run described by options. Without an endpoint override it is equivalent to
+// config.LoadDefaultConfig with config.WithRegion and the extra load options, e.g. a profile.
+func LoadAWSConfig(ctx context.Context, options *terraform.Options, region string, extra ...func(*config.LoadOptions) error) (aws.Config, error) {
+	loadOptions := append([]func(*config.LoadOptions) error{config.WithRegion(region)}, extra...)
+
+	if endpoint := options.EnvVars[awsEndpointURLEnvVar]; endpoint != "" {
+		loadOptions = append(loadOptions,
+			config.WithBaseEndpoint(endpoint),
+			config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
+				options.EnvVars[awsAccessKeyIDEnvVar],
+				options.EnvVars[awsSecretAccessKeyEnvVar],
+				"",
+			)),
+		)
+	}
+
+	return config.LoadDefaultConfig(ctx, loadOptions...)
+}
+
+// S3Options returns the S3 client options matching the Terraform run described by options.
+// Emulators are addressed with path-style requests, as the AWS provider overrides do.
+func S3Options(options *terraform.Options) func(*s3.Options) {
+	return func(o *s3.Options) {
+		if options.EnvVars[awsEndpointURLEnvVar] != "" {
+			o.UsePathStyle = true
+		}
+	}
+}
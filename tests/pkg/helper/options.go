@@ -0,0 +1,421 @@
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/repo"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+// regionVariable is the input variable through which the examples receive the AWS region.
+const regionVariable = "aws_region"
+
+var (
+	// awsRegionPattern matches AWS region names such as us-west-2 or us-gov-east-1.
+	awsRegionPattern = regexp.MustCompile(`^[a-z]{2}(-gov|-iso[a-z]?)?-[a-z]+-\d+$`)
+
+	// envVarNamePattern matches valid environment variable names.
+	envVarNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+	// regionVariablePattern matches the declaration of the aws_region input variable.
+	regionVariablePattern = regexp.MustCompile(`variable\s+"` + regionVariable + `"`)
+)
+
+// SetupOption customizes the Terraform options built by NewTerraformOptions and the
+// Setup*TerraformOptions helpers. An option returns an error when its arguments are invalid,
+// so misconfiguration is reported before Terraform is invoked.
+type SetupOption func(*setupConfig) error
+
+// setupConfig holds the optional settings applied by SetupOption values.
+type setupConfig struct {
+	copyWorkspace         bool
+	isolatedProviderCache bool
+	providerMirrorDir     string
+	awsEndpoints          *EndpointConfig
+	noRunTags             bool
+
+	varFiles           []string
+	vars               map[string]interface{}
+	region             string
+	maxRetries         int
+	timeBetweenRetries time.Duration
+	upgrade            bool
+	backendConfig      map[string]interface{}
+	env                map[string]string
+	noColor            bool
+}
+
+// Source is the Terraform directory a set of options runs against.
+type Source struct {
+	description string
+	resolve     func(dirs *repo.TFSourcesDir) string
+}
+
+// ExampleSource runs against an example, given relative to the examples directory
+// (e.g. "foundation/basic") or as an absolute path.
+func ExampleSource(examplePath string) Source {
+	return Source{
+		description: "example " + examplePath,
+		resolve: func(dirs *repo.TFSourcesDir) string {
+			if filepath.IsAbs(examplePath) {
+				return examplePath
+			}
+
+			return dirs.GetExamplesDir(examplePath)
+		},
+	}
+}
+
+// TargetSource runs against a use-case target of a module test suite (tests/modules/<module>/target/<target>).
+func TargetSource(moduleName, targetName string) Source {
+	return Source{
+		description: "target " + moduleName + "/" + targetName,
+		resolve: func(dirs *repo.TFSourcesDir) string {
+			return dirs.GetTargetDir(moduleName, targetName)
+		},
+	}
+}
+
+// ModuleSource runs against a module directory directly.
+func ModuleSource(moduleDir string) Source {
+	return Source{
+		description: "module " + moduleDir,
+		resolve: func(*repo.TFSourcesDir) string {
+			return moduleDir
+		},
+	}
+}
+
+// WithVarFiles passes the given .tfvars files, relative to the Terraform directory, to every command.
+func WithVarFiles(files ...string) SetupOption {
+	return func(c *setupConfig) error {
+		if len(files) == 0 {
+			return errors.New("at least one var file is required")
+		}
+
+		for _, file := range files {
+			if file == "" {
+				return errors.New("var file names must not be empty")
+			}
+		}
+
+		c.varFiles = append(c.varFiles, files...)
+
+		return nil
+	}
+}
+
+// WithVars sets input variables. Repeated options are merged, later values winning, and the values
+// override those of the var files, so a test can give a fixture unique names.
+func WithVars(vars map[string]interface{}) SetupOption {
+	return func(c *setupConfig) error {
+		for name, value := range vars {
+			if name == "" {
+				return errors.New("variable names must not be empty")
+			}
+
+			if c.vars == nil {
+				c.vars = map[string]interface{}{}
+			}
+
+			c.vars[name] = value
+		}
+
+		return nil
+	}
+}
+
+// WithRegion runs Terraform in the given AWS region. The region is exported as AWS_REGION and
+// AWS_DEFAULT_REGION, and passed as the aws_region variable when the configuration declares it.
+func WithRegion(region string) SetupOption {
+	return func(c *setupConfig) error {
+		if !awsRegionPattern.MatchString(region) {
+			return fmt.Errorf("%q is not a valid AWS region", region)
+		}
+
+		c.region = region
+
+		return nil
+	}
+}
+
+// WithRetries retries Terraform commands failing with one of terratest's default retryable errors
+// up to maxRetries times, waiting timeBetweenRetries between attempts.
+func WithRetries(maxRetries int, timeBetweenRetries time.Duration) SetupOption {
+	return func(c *setupConfig) error {
+		if maxRetries < 1 {
+			return fmt.Errorf("max retries must be positive, got %d", maxRetries)
+		}
+
+		if timeBetweenRetries < 0 {
+			return fmt.Errorf("time between retries must not be negative, got %s", timeBetweenRetries)
+		}
+
+		c.maxRetries = maxRetries
+		c.timeBetweenRetries = timeBetweenRetries
+
+		return nil
+	}
+}
+
+// WithUpgrade runs `terraform init -upgrade`, so local module sources are always reinstalled.
+func WithUpgrade() SetupOption {
+	return func(c *setupConfig) error {
+		c.upgrade = true
+		return nil
+	}
+}
+
+// WithBackendConfig passes -backend-config values to `terraform init`.
+func WithBackendConfig(backendConfig map[string]interface{}) SetupOption {
+	return func(c *setupConfig) error {
+		if len(backendConfig) == 0 {
+			return errors.New("backend config must not be empty")
+		}
+
+		for key, value := range backendConfig {
+			if key == "" {
+				return errors.New("backend config keys must not be empty")
+			}
+
+			if c.backendConfig == nil {
+				c.backendConfig = map[string]interface{}{}
+			}
+
+			c.backendConfig[key] = value
+		}
+
+		return nil
+	}
+}
+
+// WithEnv sets extra environment variables for Terraform. They are applied last, so they take
+// precedence over the variables set by the other options.
+func WithEnv(env map[string]string) SetupOption {
+	return func(c *setupConfig) error {
+		for name, value := range env {
+			if !envVarNamePattern.MatchString(name) {
+				return fmt.Errorf("%q is not a valid environment variable name", name)
+			}
+
+			if c.env == nil {
+				c.env = map[string]string{}
+			}
+
+			c.env[name] = value
+		}
+
+		return nil
+	}
+}
+
+// WithOptions applies several options as one, for packages that configure more than one setting.
+func WithOptions(opts ...SetupOption) SetupOption {
+	return func(c *setupConfig) error {
+		var errs []error
+		for _, opt := range opts {
+			if err := opt(c); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		return errors.Join(errs...)
+	}
+}
+
+// WithNoColor disables colored Terraform output.
+func WithNoColor() SetupOption {
+	return func(c *setupConfig) error {
+		c.noColor = true
+		return nil
+	}
+}
+
+// newSetupConfig applies the given options on top of the defaults, returning every validation error.
+func newSetupConfig(opts []SetupOption) (*setupConfig, error) {
+	cfg := &setupConfig{}
+
+	var errs []error
+	for _, opt := range opts {
+		if err := opt(cfg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if cfg.providerMirrorDir == "" {
+		if dir := os.Getenv(ProviderMirrorEnvVar); dir != "" {
+			if err := WithProviderMirror(dir)(cfg); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", ProviderMirrorEnvVar, err))
+			}
+		}
+	}
+
+	if cfg.awsEndpoints == nil {
+		endpoints, err := endpointConfigFromEnv()
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		cfg.awsEndpoints = endpoints
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// NewTerraformOptions builds Terraform options for src with the shared provider cache and the given
+// options. The test fails before Terraform is invoked if an option or the source is invalid.
+func NewTerraformOptions(t *testing.T, src Source, opts ...SetupOption) *terraform.Options {
+	options, err := NewTerraformOptionsE(t, src, opts...)
+	require.NoError(t, err, "Invalid Terraform options")
+
+	return options
+}
+
+// NewTerraformOptionsE builds Terraform options like NewTerraformOptions, returning validation errors
+// of the options and the source instead of failing the test.
+func NewTerraformOptionsE(t *testing.T, src Source, opts ...SetupOption) (*terraform.Options, error) {
+	cfg, err := newSetupConfig(opts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid options for %s: %w", src.description, err)
+	}
+
+	dirs, err := repo.NewTFSourcesDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Terraform sources directory: %w", err)
+	}
+
+	sourceDir := src.resolve(dirs)
+	if err := validateSourceDir(sourceDir, cfg); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", src.description, err)
+	}
+
+	// Set up environment variables for Terraform
+	env := setupEnv(t, cfg)
+
+	// Copy the configuration into a per-test workspace when requested
+	terraformDir := prepareTerraformDir(t, cfg, sourceDir)
+
+	vars := cfg.vars
+	if cfg.region != "" && declaresRegionVariable(terraformDir) {
+		if _, ok := vars[regionVariable]; !ok {
+			if vars == nil {
+				vars = map[string]interface{}{}
+			}
+
+			vars[regionVariable] = cfg.region
+		}
+	}
+
+	// Tag the resources of the run, keeping the tags of the fixture
+	vars, err = withRunTags(t, cfg, terraformDir, vars)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", src.description, err)
+	}
+
+	options := &terraform.Options{
+		TerraformDir:         terraformDir,
+		Vars:                 vars,
+		VarFiles:             cfg.varFiles,
+		SetVarsAfterVarFiles: true, // Vars set with WithVars override the fixture's
+		EnvVars:              env,
+		BackendConfig:        cfg.backendConfig,
+		Upgrade:              cfg.upgrade,
+		NoColor:              cfg.noColor,
+		MaxRetries:           cfg.maxRetries,
+		TimeBetweenRetries:   cfg.timeBetweenRetries,
+	}
+
+	if cfg.maxRetries > 0 {
+		options = terraform.WithDefaultRetryableErrors(t, options)
+		options.MaxRetries = cfg.maxRetries
+		options.TimeBetweenRetries = cfg.timeBetweenRetries
+	}
+
+	return options, nil
+}
+
+// validateSourceDir checks that the Terraform directory and the requested var files exist.
+func validateSourceDir(dir string, cfg *setupConfig) error {
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return fmt.Errorf("directory %s does not exist", dir)
+	}
+
+	var errs []error
+	for _, file := range cfg.varFiles {
+		path := file
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, file)
+		}
+
+		if _, err := os.Stat(path); err != nil {
+			errs = append(errs, fmt.Errorf("var file %s does not exist", path))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// declaresRegionVariable reports whether the .tf files of dir declare the aws_region input variable.
+func declaresRegionVariable(dir string) bool {
+	files, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return false
+	}
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err == nil && regionVariablePattern.Match(content) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// setupEnv returns the Terraform environment variables selected by cfg.
+func setupEnv(t *testing.T, cfg *setupConfig) map[string]string {
+	env := providerEnv(t, cfg)
+	for key, value := range awsEndpointEnv(t, cfg) {
+		env[key] = value
+	}
+
+	if cfg.region != "" {
+		env["AWS_REGION"] = cfg.region
+		env["AWS_DEFAULT_REGION"] = cfg.region
+	}
+
+	for key, value := range cfg.env {
+		env[key] = value
+	}
+
+	return env
+}
+
+// prepareTerraformDir returns the directory Terraform runs in: terraformDir itself, or a workspace
+// copy of it when requested or when files must be generated next to the configuration.
+func prepareTerraformDir(t *testing.T, cfg *setupConfig, terraformDir string) string {
+	if !cfg.copyWorkspace && cfg.awsEndpoints == nil {
+		return terraformDir
+	}
+
+	terraformDir = CopyToWorkspace(t, terraformDir)
+
+	if cfg.awsEndpoints != nil {
+		err := WriteAWSEndpointOverrides(terraformDir, *cfg.awsEndpoints)
+		require.NoError(t, err, "Failed to write AWS endpoint overrides")
+	}
+
+	return terraformDir
+}
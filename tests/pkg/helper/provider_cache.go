@@ -0,0 +1,157 @@
+package helper
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+// Environment variables controlling the provider plugin cache.
+const (
+	// pluginCacheEnvVar is the variable Terraform reads the plugin cache directory from.
+	pluginCacheEnvVar = "TF_PLUGIN_CACHE_DIR"
+
+	// SharedProviderCacheEnvVar overrides the location of the shared provider cache used by the harness.
+	SharedProviderCacheEnvVar = "TF_TEST_SHARED_PLUGIN_CACHE_DIR"
+)
+
+// sharedProviderCacheLockFile is the file locked while `terraform init` writes to the shared cache.
+const sharedProviderCacheLockFile = ".init.lock"
+
+var (
+	sharedProviderCacheOnce sync.Once
+	sharedProviderCacheDir  string
+	sharedProviderCacheErr  error
+
+	// sharedProviderCacheMu serializes `terraform init` runs of this process that use the shared cache.
+	// The file lock taken alongside it serializes them across the processes started by `go test ./...`.
+	sharedProviderCacheMu sync.Mutex
+)
+
+// SharedProviderCacheDir returns the provider plugin cache shared by every test of the `go test` process.
+// The directory is created on first use, defaults to a stable location under the system temporary
+// directory so later runs reuse already downloaded providers, and can be moved with
+// TF_TEST_SHARED_PLUGIN_CACHE_DIR.
+func SharedProviderCacheDir(t *testing.T) string {
+	sharedProviderCacheOnce.Do(func() {
+		dir := os.Getenv(SharedProviderCacheEnvVar)
+		if dir == "" {
+			dir = filepath.Join(os.TempDir(), "terraform-aws-codeartifact", "tf-plugin-cache")
+		}
+
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			sharedProviderCacheErr = fmt.Errorf("failed to create shared provider cache %s: %w", dir, err)
+			return
+		}
+
+		sharedProviderCacheDir = dir
+	})
+
+	require.NoError(t, sharedProviderCacheErr, "Failed to set up shared Terraform provider cache")
+
+	return sharedProviderCacheDir
+}
+
+// WithIsolatedProviderCache gives the test its own provider plugin cache instead of the shared one.
+// Use it for tests that must observe a cold cache or that tamper with installed providers.
+func WithIsolatedProviderCache() SetupOption {
+	return func(c *setupConfig) error {
+		c.isolatedProviderCache = true
+		return nil
+	}
+}
+
+// isolatedProviderCacheDir creates a provider cache owned by a single test and removed through t.Cleanup.
+func isolatedProviderCacheDir(t *testing.T) string {
+	tempDir, err := os.MkdirTemp("", "tf-plugin-cache-")
+	require.NoError(t, err, "Failed to create temporary directory for Terraform provider cache")
+
+	// Clean up the temp directory when the test completes
+	t.Cleanup(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	return tempDir
+}
+
+// providerEnv returns the Terraform environment variables for the provider cache selected by cfg and,
+// when configured, the offline provider mirror.
+func providerEnv(t *testing.T, cfg *setupConfig) map[string]string {
+	var cacheDir string
+	if cfg.isolatedProviderCache {
+		cacheDir = isolatedProviderCacheDir(t)
+		t.Logf("🔧 Using isolated provider cache at: %s", cacheDir)
+	} else {
+		cacheDir = SharedProviderCacheDir(t)
+		t.Logf("🔧 Using shared provider cache at: %s", cacheDir)
+	}
+
+	env := map[string]string{
+		pluginCacheEnvVar:         cacheDir,
+		"TF_SKIP_PROVIDER_VERIFY": "1", // Skip provider verification to avoid issues with provider caching
+	}
+
+	for key, value := range providerMirrorEnv(t, cfg) {
+		env[key] = value
+	}
+
+	return env
+}
+
+// Init runs `terraform init`, holding the shared provider cache lock when the options use the shared cache.
+// The test fails if init fails.
+func Init(t *testing.T, options *terraform.Options) string {
+	out, err := InitE(t, options)
+	require.NoError(t, err, "Terraform init failed")
+
+	return out
+}
+
+// InitE runs `terraform init`, holding the shared provider cache lock when the options use the shared cache.
+// Terraform does not guarantee that concurrent writers to one plugin cache are safe, so parallel tests
+// take turns populating it; once a provider is cached, init only links it and the lock is held briefly.
+func InitE(t *testing.T, options *terraform.Options) (string, error) {
+	if sharedProviderCacheDir == "" || options.EnvVars[pluginCacheEnvVar] != sharedProviderCacheDir {
+		return terraform.InitE(t, options)
+	}
+
+	unlock, err := lockSharedProviderCache()
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	return terraform.InitE(t, options)
+}
+
+// lockSharedProviderCache takes the in-process and cross-process locks of the shared provider cache.
+func lockSharedProviderCache() (func(), error) {
+	sharedProviderCacheMu.Lock()
+
+	lockPath := filepath.Join(sharedProviderCacheDir, sharedProviderCacheLockFile)
+
+	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		sharedProviderCacheMu.Unlock()
+		return nil, fmt.Errorf("failed to open provider cache lock %s: %w", lockPath, err)
+	}
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		f.Close()
+		sharedProviderCacheMu.Unlock()
+
+		return nil, fmt.Errorf("failed to lock provider cache %s: %w", lockPath, err)
+	}
+
+	return func() {
+		_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+		sharedProviderCacheMu.Unlock()
+	}, nil
+}
@@ -0,0 +1,91 @@
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// cliConfigEnvVar is the variable Terraform reads the CLI configuration file from.
+	cliConfigEnvVar = "TF_CLI_CONFIG_FILE"
+
+	// ProviderMirrorEnvVar enables the offline provider mirror for every test when set to a mirror directory.
+	ProviderMirrorEnvVar = "TF_TEST_PROVIDER_MIRROR_DIR"
+)
+
+// providerMirrorConfigTemplate installs every provider from a filesystem mirror and never from a registry.
+const providerMirrorConfigTemplate = `provider_installation {
+  filesystem_mirror {
+    path = %q
+  }
+}
+`
+
+// WithProviderMirror makes `terraform init` install providers only from the filesystem mirror at dir,
+// as populated by `go run ./cmd/provider-mirror`. Use it on agents without internet access.
+// Setting TF_TEST_PROVIDER_MIRROR_DIR enables the mirror for every test without this option.
+func WithProviderMirror(dir string) SetupOption {
+	return func(c *setupConfig) error {
+		if dir == "" {
+			return errors.New("provider mirror directory must not be empty")
+		}
+
+		info, err := os.Stat(dir)
+		if err != nil || !info.IsDir() {
+			return fmt.Errorf("provider mirror %s is not an existing directory", dir)
+		}
+
+		c.providerMirrorDir = dir
+
+		return nil
+	}
+}
+
+// WriteProviderMirrorConfig writes a Terraform CLI configuration into dir that installs providers
+// from the filesystem mirror at mirrorDir only, and returns the path of the written file.
+func WriteProviderMirrorConfig(dir, mirrorDir string) (string, error) {
+	absMirrorDir, err := filepath.Abs(mirrorDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve provider mirror %s: %w", mirrorDir, err)
+	}
+
+	info, err := os.Stat(absMirrorDir)
+	if err != nil {
+		return "", fmt.Errorf("provider mirror %s is not available: %w", absMirrorDir, err)
+	}
+
+	if !info.IsDir() {
+		return "", fmt.Errorf("provider mirror %s is not a directory", absMirrorDir)
+	}
+
+	configPath := filepath.Join(dir, "terraform.tfrc")
+	content := fmt.Sprintf(providerMirrorConfigTemplate, filepath.ToSlash(absMirrorDir))
+
+	if err := os.WriteFile(configPath, []byte(content), 0o600); err != nil {
+		return "", fmt.Errorf("failed to write Terraform CLI configuration %s: %w", configPath, err)
+	}
+
+	return configPath, nil
+}
+
+// providerMirrorEnv returns the Terraform environment variables enabling the provider mirror selected
+// by cfg, or nil when no mirror is configured.
+func providerMirrorEnv(t *testing.T, cfg *setupConfig) map[string]string {
+	if cfg.providerMirrorDir == "" {
+		return nil
+	}
+
+	configPath, err := WriteProviderMirrorConfig(t.TempDir(), cfg.providerMirrorDir)
+	require.NoError(t, err, "Failed to configure the offline provider mirror")
+
+	t.Logf("📦 Using offline provider mirror at: %s", cfg.providerMirrorDir)
+
+	return map[string]string{
+		cliConfigEnvVar: configPath,
+	}
+}
@@ -0,0 +1,247 @@
+package helper
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/naming"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/repo"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// Tags added to the resources of every test run, so leaked resources can be attributed to a run
+// and removed by cmd/sweeper.
+const (
+	// RunIDTagKey holds the run ID of the `go test` process, the same as in the names of pkg/naming.
+	RunIDTagKey = "terratest:run-id"
+
+	// TestTagKey holds the name of the test that created the resource.
+	TestTagKey = "terratest:test"
+
+	// GitSHATagKey holds the commit the tests ran against.
+	GitSHATagKey = "terratest:git-sha"
+
+	// ExpiresAtTagKey holds the RFC 3339 time after which the resource is considered leaked.
+	ExpiresAtTagKey = "terratest:expires-at"
+)
+
+const (
+	// RunTTLEnvVar overrides how long after its creation a test resource is considered leaked, e.g. "6h".
+	RunTTLEnvVar = "TF_TEST_RESOURCE_TTL"
+
+	// GitSHAEnvVar sets the commit recorded in the git SHA tag instead of asking git.
+	GitSHAEnvVar = "TF_TEST_GIT_SHA"
+
+	// defaultRunTTL matches the default age threshold of cmd/sweeper.
+	defaultRunTTL = 3 * time.Hour
+
+	// tagsVariable is the input variable through which the modules and examples receive their tags.
+	tagsVariable = "tags"
+
+	// maxTagValueLength is the longest tag value accepted by every AWS service.
+	maxTagValueLength = 256
+)
+
+// invalidTagValueChars matches the characters AWS services reject in tag values.
+var invalidTagValueChars = regexp.MustCompile(`[^\p{L}\p{Z}\p{N}_.:/=+\-@]`)
+
+var (
+	gitSHAOnce  sync.Once
+	gitSHAValue string
+)
+
+// WithoutRunTags keeps the tags variable untouched, e.g. for plans compared to a fixed snapshot.
+// By default the run ID, test name, git SHA and expiry tags are merged into it.
+func WithoutRunTags() SetupOption {
+	return func(c *setupConfig) error {
+		c.noRunTags = true
+		return nil
+	}
+}
+
+// RunTags returns the standard tags merged into the tags variable of the test's configurations.
+func RunTags(t *testing.T) (map[string]string, error) {
+	namer, err := naming.DefaultE()
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := defaultRunTTL
+	if value := os.Getenv(RunTTLEnvVar); value != "" {
+		if ttl, err = time.ParseDuration(value); err != nil || ttl <= 0 {
+			return nil, fmt.Errorf("invalid %s %q: must be a positive duration", RunTTLEnvVar, value)
+		}
+	}
+
+	return map[string]string{
+		RunIDTagKey:     namer.RunID(),
+		TestTagKey:      tagValue(t.Name()),
+		GitSHATagKey:    tagValue(gitSHA()),
+		ExpiresAtTagKey: time.Now().Add(ttl).UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// withRunTags returns vars with the standard tags merged into the tags variable, when the
+// configuration in dir declares one. The tags the run would otherwise use are kept: those passed
+// with WithVars, else those of the last var file assigning them, else the variable default.
+func withRunTags(t *testing.T, cfg *setupConfig, dir string, vars map[string]interface{}) (map[string]interface{}, error) {
+	if cfg.noRunTags {
+		return vars, nil
+	}
+
+	variables, err := repo.ParseVariables(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	declared, ok := variables[tagsVariable]
+	if !ok {
+		return vars, nil
+	}
+
+	tags, err := configuredTags(dir, cfg.varFiles, declared, vars)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve the %s variable: %w", tagsVariable, err)
+	}
+
+	standard, err := RunTags(t)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range standard {
+		tags[key] = value
+	}
+
+	merged := make(map[string]interface{}, len(vars)+1)
+	for name, value := range vars {
+		merged[name] = value
+	}
+
+	merged[tagsVariable] = tags
+
+	return merged, nil
+}
+
+// configuredTags returns the tags the run would use without the standard tags.
+func configuredTags(dir string, varFiles []string, declared repo.Variable, vars map[string]interface{}) (map[string]string, error) {
+	if value, ok := vars[tagsVariable]; ok {
+		return goStringMap(value)
+	}
+
+	for i := len(varFiles) - 1; i >= 0; i-- {
+		path := varFiles[i]
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+
+		assignments, err := repo.ParseVarFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if assignment, ok := assignments[tagsVariable]; ok {
+			value, err := assignment.Value()
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+
+			return ctyStringMap(value)
+		}
+	}
+
+	if declared.Default != cty.NilVal {
+		return ctyStringMap(declared.Default)
+	}
+
+	return map[string]string{}, nil
+}
+
+// goStringMap copies a tags value passed with WithVars.
+func goStringMap(value interface{}) (map[string]string, error) {
+	tags := map[string]string{}
+
+	switch v := value.(type) {
+	case nil:
+	case map[string]string:
+		for key, item := range v {
+			tags[key] = item
+		}
+	case map[string]interface{}:
+		for key, item := range v {
+			tags[key] = fmt.Sprint(item)
+		}
+	default:
+		return nil, fmt.Errorf("expected a map, got %T", value)
+	}
+
+	return tags, nil
+}
+
+// ctyStringMap converts a map or object value to a map of strings; null yields an empty map.
+func ctyStringMap(value cty.Value) (map[string]string, error) {
+	tags := map[string]string{}
+
+	if value.IsNull() {
+		return tags, nil
+	}
+
+	if !value.Type().IsMapType() && !value.Type().IsObjectType() {
+		return nil, fmt.Errorf("expected a map, got %s", value.Type().FriendlyName())
+	}
+
+	for key, item := range value.AsValueMap() {
+		str, err := convert.Convert(item, cty.String)
+		if err != nil || str.IsNull() {
+			return nil, fmt.Errorf("tag %q is not a string", key)
+		}
+
+		tags[key] = str.AsString()
+	}
+
+	return tags, nil
+}
+
+// gitSHA returns the commit of the repository, from TF_TEST_GIT_SHA or git, once per process.
+func gitSHA() string {
+	gitSHAOnce.Do(func() {
+		gitSHAValue = "unknown"
+
+		if value := os.Getenv(GitSHAEnvVar); value != "" {
+			gitSHAValue = value
+			return
+		}
+
+		dirs, err := repo.NewTFSourcesDir()
+		if err != nil {
+			return
+		}
+
+		cmd := exec.Command("git", "rev-parse", "HEAD")
+		cmd.Dir = dirs.GetRootDir()
+
+		if out, err := cmd.Output(); err == nil {
+			gitSHAValue = strings.TrimSpace(string(out))
+		}
+	})
+
+	return gitSHAValue
+}
+
+// tagValue replaces the characters AWS rejects in tag values and truncates the value.
+func tagValue(value string) string {
+	value = invalidTagValueChars.ReplaceAllString(value, "_")
+	if len(value) > maxTagValueLength {
+		value = value[:maxTagValueLength]
+	}
+
+	return value
+}
@@ -0,0 +1,180 @@
+package helper
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/repo"
+	"github.com/stretchr/testify/require"
+)
+
+// localSourcePattern matches module sources given as a relative path, e.g. source = "../../../modules/domain".
+var localSourcePattern = regexp.MustCompile(`source\s*=\s*"(\.{1,2}/[^"]*)"`)
+
+// skippedWorkspaceEntries lists files and directories that are never copied into a workspace because
+// they hold per-run Terraform state that must not leak between tests.
+var skippedWorkspaceEntries = map[string]bool{
+	".terraform":                   true,
+	"terraform.tfstate":            true,
+	"terraform.tfstate.backup":     true,
+	".terraform.tfstate.lock.info": true,
+}
+
+// WithWorkspaceCopy copies the Terraform directory, and every module it references by relative path,
+// into a temporary workspace owned by the test. Parallel tests that use the same example then get
+// their own .terraform folder, lock file and local state.
+func WithWorkspaceCopy() SetupOption {
+	return func(c *setupConfig) error {
+		c.copyWorkspace = true
+		return nil
+	}
+}
+
+// CopyToWorkspace copies terraformDir into a temporary workspace and returns the path of the copy.
+// Modules referenced through relative sources are copied too, keeping their position relative to the
+// repository root so that the relative paths keep resolving. The workspace is removed through t.Cleanup.
+func CopyToWorkspace(t *testing.T, terraformDir string) string {
+	dirs, err := repo.NewTFSourcesDir()
+	require.NoError(t, err, "Failed to get Terraform sources directory")
+
+	workspace, err := os.MkdirTemp("", "tf-workspace-")
+	require.NoError(t, err, "Failed to create temporary Terraform workspace")
+
+	// Clean up the workspace when the test completes
+	t.Cleanup(func() {
+		os.RemoveAll(workspace)
+	})
+
+	copied, err := copyWithLocalModules(dirs.GetRootDir(), workspace, terraformDir)
+	require.NoError(t, err, "Failed to copy %s into workspace", terraformDir)
+
+	t.Logf("📂 Using isolated workspace at: %s", workspace)
+
+	return copied
+}
+
+// copyWithLocalModules copies dir and, transitively, every local module it references from rootDir
+// into workspace. It returns the path of dir inside the workspace.
+func copyWithLocalModules(rootDir, workspace, dir string) (string, error) {
+	pending := []string{dir}
+	visited := map[string]bool{}
+
+	for len(pending) > 0 {
+		current := filepath.Clean(pending[0])
+		pending = pending[1:]
+
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+
+		rel, err := filepath.Rel(rootDir, current)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return "", fmt.Errorf("directory %s is outside the repository root %s", current, rootDir)
+		}
+
+		if err := copyDir(current, filepath.Join(workspace, rel)); err != nil {
+			return "", err
+		}
+
+		sources, err := localModuleSources(current)
+		if err != nil {
+			return "", err
+		}
+
+		pending = append(pending, sources...)
+	}
+
+	rel, err := filepath.Rel(rootDir, filepath.Clean(dir))
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(workspace, rel), nil
+}
+
+// localModuleSources returns the absolute paths of the modules referenced by relative sources in the .tf files of dir.
+func localModuleSources(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Terraform files in %s: %w", dir, err)
+	}
+
+	var sources []string
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+
+		for _, match := range localSourcePattern.FindAllStringSubmatch(string(content), -1) {
+			sources = append(sources, filepath.Join(dir, match[1]))
+		}
+	}
+
+	return sources, nil
+}
+
+// copyDir recursively copies src into dst, skipping Terraform working state.
+func copyDir(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if skippedWorkspaceEntries[info.Name()] && path != src {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dst, rel)
+
+		if info.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		return copyFile(path, target, info.Mode().Perm())
+	})
+}
+
+// copyFile copies a single regular file, creating its parent directory when needed.
+func copyFile(src, dst string, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
@@ -0,0 +1,128 @@
+package iampolicy
+
+import (
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/plan"
+	"github.com/stretchr/testify/require"
+)
+
+// Expected describes a statement a test expects. Empty fields are not compared; the others must
+// match as sets, so the order of the elements and the string or list form do not matter.
+type Expected struct {
+	Effect     string
+	Principals map[string][]string
+	Actions    []string
+	Resources  []string
+	Conditions map[string]map[string][]string
+}
+
+// RequirePlannedDocument parses the planned policy document attribute of a resource. The test fails
+// if the resource is not planned or the attribute is unknown or invalid.
+func RequirePlannedDocument(t *testing.T, p *plan.Plan, address, attribute string) *Document {
+	t.Helper()
+
+	doc, err := FromPlan(p, address, attribute)
+	require.NoError(t, err, "Planned %s of %q should be a known policy document", attribute, address)
+
+	return doc
+}
+
+// RequireStatement fails the test unless the document has a statement with the given SID.
+func RequireStatement(t *testing.T, doc *Document, sid string) *Statement {
+	t.Helper()
+
+	statement, ok := doc.Statement(sid)
+	require.Truef(t, ok, "Policy should contain statement %q; statements: %v", sid, doc.SIDs())
+
+	return statement
+}
+
+// RequireNoStatement fails the test if the document has a statement with the given SID.
+func RequireNoStatement(t *testing.T, doc *Document, sid string) {
+	t.Helper()
+
+	_, ok := doc.Statement(sid)
+	require.Falsef(t, ok, "Policy should not contain statement %q", sid)
+}
+
+// RequireSIDs fails the test unless the SIDs of the document's statements are exactly sids, in any order.
+func RequireSIDs(t *testing.T, doc *Document, sids ...string) {
+	t.Helper()
+
+	require.ElementsMatch(t, sids, doc.SIDs(), "Policy should contain exactly the expected statements")
+}
+
+// RequireStatementMatches fails the test unless the statement with the given SID matches every
+// non-empty field of expected.
+func RequireStatementMatches(t *testing.T, doc *Document, sid string, expected Expected) {
+	t.Helper()
+
+	statement := RequireStatement(t, doc, sid)
+
+	if expected.Effect != "" {
+		require.Equal(t, expected.Effect, statement.Effect, "Unexpected effect of statement %q", sid)
+	}
+
+	if expected.Principals != nil {
+		require.Equal(t, normalizePrincipals(expected.Principals), statement.Principals, "Unexpected principals of statement %q", sid)
+	}
+
+	if expected.Actions != nil {
+		require.Equal(t, actionSet(expected.Actions), statement.Actions, "Unexpected actions of statement %q", sid)
+	}
+
+	if expected.Resources != nil {
+		require.Equal(t, set(expected.Resources), statement.Resources, "Unexpected resources of statement %q", sid)
+	}
+
+	if expected.Conditions != nil {
+		require.Equal(t, normalizeConditions(expected.Conditions), statement.Conditions, "Unexpected conditions of statement %q", sid)
+	}
+}
+
+// RequireDecision fails the test unless the document decides the request as expected.
+func RequireDecision(t *testing.T, doc *Document, req Request, expected Decision) {
+	t.Helper()
+
+	evaluation, err := doc.Evaluate(req)
+	require.NoError(t, err, "Failed to evaluate the policy")
+	require.Equalf(t, expected, evaluation.Decision,
+		"Unexpected decision for %s calling %s on %s (matching statements: %v)", req.Principal, req.Action, req.Resource, evaluation.Statements)
+}
+
+// RequireEquivalent fails the test unless the documents have the same version and the same
+// statements in any order, showing the differing statements otherwise.
+func RequireEquivalent(t *testing.T, expected, actual *Document) {
+	t.Helper()
+
+	if expected.Equivalent(actual) {
+		return
+	}
+
+	require.Equal(t, expected.Version, actual.Version, "Policy documents should have the same version")
+	require.Equal(t, expected.sortedStatements(), actual.sortedStatements(), "Policy documents should have the same statements")
+}
+
+// normalizePrincipals returns principals with every identifier list as a set.
+func normalizePrincipals(principals map[string][]string) map[string][]string {
+	normalized := make(map[string][]string, len(principals))
+	for principalType, identifiers := range principals {
+		normalized[principalType] = set(identifiers)
+	}
+
+	return normalized
+}
+
+// normalizeConditions returns conditions with every value list as a set.
+func normalizeConditions(conditions map[string]map[string][]string) map[string]map[string][]string {
+	normalized := make(map[string]map[string][]string, len(conditions))
+	for operator, keys := range conditions {
+		normalized[operator] = make(map[string][]string, len(keys))
+		for key, values := range keys {
+			normalized[operator][key] = set(values)
+		}
+	}
+
+	return normalized
+}
@@ -0,0 +1,450 @@
+// Package iampolicy parses IAM policy documents, such as the policy_document outputs of the
+// domain-permissions and repository-permissions modules and the planned policy attributes, into a
+// normalized form for semantic assertions. Every list-valued element is kept as a sorted set, so a
+// single string and a one-element list compare equal and element order never matters.
+package iampolicy
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/plan"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+// anyPrincipal is the principal type "Principal": "*" is normalized to.
+const anyPrincipal = "AWS"
+
+// Document is a parsed IAM policy document.
+type Document struct {
+	Version    string
+	ID         string
+	Statements []Statement
+}
+
+// Statement is a normalized policy statement. Principals map a principal type ("AWS", "Service",
+// "Federated", ...) to its identifiers, and Conditions map an operator ("StringEquals", ...) to
+// its condition keys and their values.
+type Statement struct {
+	Sid           string
+	Effect        string
+	Principals    map[string][]string
+	NotPrincipals map[string][]string
+	Actions       []string
+	NotActions    []string
+	Resources     []string
+	NotResources  []string
+	Conditions    map[string]map[string][]string
+}
+
+// rawDocument is the JSON representation of a policy document.
+type rawDocument struct {
+	Version   string          `json:"Version"`
+	ID        string          `json:"Id"`
+	Statement json.RawMessage `json:"Statement"`
+}
+
+// rawStatement is the JSON representation of a statement, before normalization.
+type rawStatement struct {
+	Sid          string                                `json:"Sid"`
+	Effect       string                                `json:"Effect"`
+	Principal    json.RawMessage                       `json:"Principal"`
+	NotPrincipal json.RawMessage                       `json:"NotPrincipal"`
+	Action       json.RawMessage                       `json:"Action"`
+	NotAction    json.RawMessage                       `json:"NotAction"`
+	Resource     json.RawMessage                       `json:"Resource"`
+	NotResource  json.RawMessage                       `json:"NotResource"`
+	Condition    map[string]map[string]json.RawMessage `json:"Condition"`
+}
+
+// Parse decodes a JSON policy document.
+func Parse(document string) (*Document, error) {
+	var raw rawDocument
+	if err := json.Unmarshal([]byte(document), &raw); err != nil {
+		return nil, fmt.Errorf("failed to decode policy document: %w", err)
+	}
+
+	var statements []rawStatement
+	if err := decodeOneOrMany(raw.Statement, &statements); err != nil {
+		return nil, fmt.Errorf("failed to decode policy statements: %w", err)
+	}
+
+	doc := &Document{Version: raw.Version, ID: raw.ID}
+
+	for i, rs := range statements {
+		statement, err := rs.normalize()
+		if err != nil {
+			return nil, fmt.Errorf("statement %d: %w", i, err)
+		}
+
+		doc.Statements = append(doc.Statements, statement)
+	}
+
+	return doc, nil
+}
+
+// FromOutput parses the policy document held by a Terraform output, sensitive or not. The test
+// fails if the output is missing, null or not a valid document.
+func FromOutput(t *testing.T, options *terraform.Options, name string) *Document {
+	t.Helper()
+
+	value, err := terraform.OutputE(t, options, name)
+	require.NoError(t, err, "Failed to read output %q", name)
+	require.NotEmpty(t, value, "Output %q should hold a policy document", name)
+
+	doc, err := Parse(value)
+	require.NoError(t, err, "Output %q should be a valid policy document", name)
+
+	return doc
+}
+
+// FromPlan parses the planned policy document attribute of a resource, e.g. the policy_document of
+// an aws_codeartifact_domain_permissions_policy or the policy of an aws_iam_role_policy.
+func FromPlan(p *plan.Plan, address, attribute string) (*Document, error) {
+	rc, ok := p.ResourceChange(address)
+	if !ok {
+		return nil, fmt.Errorf("plan has no resource %q", address)
+	}
+
+	value, ok := rc.Change.AfterAttribute(attribute)
+	if !ok {
+		return nil, fmt.Errorf("attribute %q of %q is not known until apply", attribute, address)
+	}
+
+	document, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("attribute %q of %q is a %T, not a policy document", attribute, address, value)
+	}
+
+	return Parse(document)
+}
+
+// FromPolicyDocumentData builds a document from the statement blocks of an aws_iam_policy_document
+// data source planned for reading, for plans where its rendered json is not known yet. Values only
+// known after apply are left empty.
+func FromPolicyDocumentData(p *plan.Plan, address string) (*Document, error) {
+	rc, ok := p.ResourceChange(address)
+	if !ok {
+		return nil, fmt.Errorf("plan has no resource %q", address)
+	}
+
+	if rc.Type != "aws_iam_policy_document" {
+		return nil, fmt.Errorf("%q is a %s, not an aws_iam_policy_document", address, rc.Type)
+	}
+
+	version, _ := rc.Change.AfterAttribute("version")
+	doc := &Document{Version: stringValue(version)}
+
+	blocks, _ := rc.Change.AfterAttribute("statement")
+	list, _ := blocks.([]interface{})
+
+	for _, item := range list {
+		block, _ := item.(map[string]interface{})
+
+		doc.Statements = append(doc.Statements, Statement{
+			Sid:           stringValue(block["sid"]),
+			Effect:        stringValue(block["effect"]),
+			Principals:    principalBlocks(block["principals"]),
+			NotPrincipals: principalBlocks(block["not_principals"]),
+			Actions:       actionSet(stringList(block["actions"])),
+			NotActions:    actionSet(stringList(block["not_actions"])),
+			Resources:     set(stringList(block["resources"])),
+			NotResources:  set(stringList(block["not_resources"])),
+			Conditions:    conditionBlocks(block["condition"]),
+		})
+	}
+
+	return doc, nil
+}
+
+// Statement returns the statement with the given SID.
+func (d *Document) Statement(sid string) (*Statement, bool) {
+	for i := range d.Statements {
+		if d.Statements[i].Sid == sid {
+			return &d.Statements[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// SIDs returns the SIDs of the statements, in document order. Statements without a SID are skipped.
+func (d *Document) SIDs() []string {
+	var sids []string
+	for _, s := range d.Statements {
+		if s.Sid != "" {
+			sids = append(sids, s.Sid)
+		}
+	}
+
+	return sids
+}
+
+// Equivalent reports whether two documents have the same version and the same statements, in any
+// order. Statements are compared in their normalized form, so the string or list form of an element
+// and the order of its values do not matter.
+func (d *Document) Equivalent(other *Document) bool {
+	return d.Version == other.Version && reflect.DeepEqual(d.sortedStatements(), other.sortedStatements())
+}
+
+// sortedStatements returns the statements ordered by their JSON encoding, so that documents listing
+// the same statements in a different order compare equal.
+func (d *Document) sortedStatements() []Statement {
+	type keyed struct {
+		key       string
+		statement Statement
+	}
+
+	statements := make([]keyed, len(d.Statements))
+	for i, s := range d.Statements {
+		encoded, _ := json.Marshal(s)
+		statements[i] = keyed{key: string(encoded), statement: s}
+	}
+
+	sort.Slice(statements, func(i, j int) bool { return statements[i].key < statements[j].key })
+
+	sorted := make([]Statement, len(statements))
+	for i, s := range statements {
+		sorted[i] = s.statement
+	}
+
+	return sorted
+}
+
+// normalize converts the polymorphic JSON elements of a statement to sets.
+func (rs rawStatement) normalize() (Statement, error) {
+	s := Statement{Sid: rs.Sid, Effect: rs.Effect}
+
+	var err error
+
+	if s.Principals, err = decodePrincipal(rs.Principal); err != nil {
+		return s, fmt.Errorf("invalid Principal: %w", err)
+	}
+
+	if s.NotPrincipals, err = decodePrincipal(rs.NotPrincipal); err != nil {
+		return s, fmt.Errorf("invalid NotPrincipal: %w", err)
+	}
+
+	for _, field := range []struct {
+		name   string
+		raw    json.RawMessage
+		target *[]string
+		action bool
+	}{
+		{"Action", rs.Action, &s.Actions, true},
+		{"NotAction", rs.NotAction, &s.NotActions, true},
+		{"Resource", rs.Resource, &s.Resources, false},
+		{"NotResource", rs.NotResource, &s.NotResources, false},
+	} {
+		values, err := decodeStrings(field.raw)
+		if err != nil {
+			return s, fmt.Errorf("invalid %s: %w", field.name, err)
+		}
+
+		if field.action {
+			*field.target = actionSet(values)
+		} else {
+			*field.target = set(values)
+		}
+	}
+
+	if len(rs.Condition) > 0 {
+		s.Conditions = make(map[string]map[string][]string, len(rs.Condition))
+
+		for operator, keys := range rs.Condition {
+			s.Conditions[operator] = make(map[string][]string, len(keys))
+
+			for key, raw := range keys {
+				values, err := decodeStrings(raw)
+				if err != nil {
+					return s, fmt.Errorf("invalid Condition %s %s: %w", operator, key, err)
+				}
+
+				s.Conditions[operator][key] = set(values)
+			}
+		}
+	}
+
+	return s, nil
+}
+
+// decodeOneOrMany decodes a JSON object or array of objects into target, a pointer to a slice.
+func decodeOneOrMany(raw json.RawMessage, target *[]rawStatement) error {
+	trimmed := strings.TrimSpace(string(raw))
+	if trimmed == "" || trimmed == "null" {
+		return nil
+	}
+
+	if strings.HasPrefix(trimmed, "{") {
+		var single rawStatement
+		if err := json.Unmarshal(raw, &single); err != nil {
+			return err
+		}
+
+		*target = []rawStatement{single}
+
+		return nil
+	}
+
+	return json.Unmarshal(raw, target)
+}
+
+// decodePrincipal decodes "*" or a map of principal types to one or many identifiers.
+func decodePrincipal(raw json.RawMessage) (map[string][]string, error) {
+	trimmed := strings.TrimSpace(string(raw))
+	if trimmed == "" || trimmed == "null" {
+		return nil, nil
+	}
+
+	var wildcard string
+	if err := json.Unmarshal(raw, &wildcard); err == nil {
+		return map[string][]string{anyPrincipal: {wildcard}}, nil
+	}
+
+	var byType map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &byType); err != nil {
+		return nil, err
+	}
+
+	principals := make(map[string][]string, len(byType))
+	for principalType, identifiers := range byType {
+		values, err := decodeStrings(identifiers)
+		if err != nil {
+			return nil, err
+		}
+
+		principals[principalType] = set(values)
+	}
+
+	return principals, nil
+}
+
+// decodeStrings decodes a scalar or an array of scalars into strings.
+func decodeStrings(raw json.RawMessage) ([]string, error) {
+	trimmed := strings.TrimSpace(string(raw))
+	if trimmed == "" || trimmed == "null" {
+		return nil, nil
+	}
+
+	var many []interface{}
+	if err := json.Unmarshal(raw, &many); err != nil {
+		var one interface{}
+		if err := json.Unmarshal(raw, &one); err != nil {
+			return nil, err
+		}
+
+		many = []interface{}{one}
+	}
+
+	values := make([]string, 0, len(many))
+	for _, v := range many {
+		switch v.(type) {
+		case map[string]interface{}, []interface{}:
+			return nil, fmt.Errorf("unexpected nested value %v", v)
+		}
+
+		values = append(values, stringValue(v))
+	}
+
+	return values, nil
+}
+
+// principalBlocks converts the principals blocks of an aws_iam_policy_document statement.
+func principalBlocks(value interface{}) map[string][]string {
+	blocks, _ := value.([]interface{})
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	principals := map[string][]string{}
+	for _, item := range blocks {
+		block, _ := item.(map[string]interface{})
+		principalType := stringValue(block["type"])
+		principals[principalType] = set(append(principals[principalType], stringList(block["identifiers"])...))
+	}
+
+	return principals
+}
+
+// conditionBlocks converts the condition blocks of an aws_iam_policy_document statement.
+func conditionBlocks(value interface{}) map[string]map[string][]string {
+	blocks, _ := value.([]interface{})
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	conditions := map[string]map[string][]string{}
+	for _, item := range blocks {
+		block, _ := item.(map[string]interface{})
+		operator, key := stringValue(block["test"]), stringValue(block["variable"])
+
+		if conditions[operator] == nil {
+			conditions[operator] = map[string][]string{}
+		}
+
+		conditions[operator][key] = set(append(conditions[operator][key], stringList(block["values"])...))
+	}
+
+	return conditions
+}
+
+// stringList converts a decoded JSON list to strings; anything else yields nil.
+func stringList(value interface{}) []string {
+	list, _ := value.([]interface{})
+
+	values := make([]string, 0, len(list))
+	for _, v := range list {
+		values = append(values, stringValue(v))
+	}
+
+	return values
+}
+
+// stringValue renders a decoded JSON scalar as IAM compares it; null yields "".
+func stringValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// set returns the sorted, de-duplicated values, or nil when there are none.
+func set(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+
+	sort.Strings(unique)
+
+	return unique
+}
+
+// actionSet returns the set of actions. IAM matches action names case-insensitively, so they are
+// compared in lower case.
+func actionSet(values []string) []string {
+	lower := make([]string, len(values))
+	for i, v := range values {
+		lower[i] = strings.ToLower(v)
+	}
+
+	return set(lower)
+}
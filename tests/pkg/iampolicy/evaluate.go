@@ -0,0 +1,364 @@
+package iampolicy
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Decision is the outcome of evaluating a request against a policy.
+type Decision string
+
+// Decisions of Evaluate. An explicit deny overrides any allow; a request no statement matches is
+// implicitly denied.
+const (
+	Allow        Decision = "allow"
+	ExplicitDeny Decision = "explicit-deny"
+	ImplicitDeny Decision = "implicit-deny"
+)
+
+// Condition keys Evaluate derives from the request principal when the context does not set them.
+const (
+	PrincipalARNKey     = "aws:PrincipalArn"
+	PrincipalAccountKey = "aws:PrincipalAccount"
+)
+
+// ifExistsSuffix makes a condition operator match when the key is missing from the request context.
+const ifExistsSuffix = "IfExists"
+
+var (
+	// accountIDPattern matches a bare AWS account ID principal.
+	accountIDPattern = regexp.MustCompile(`^[0-9]{12}$`)
+
+	// principalARNPattern splits an IAM or STS principal ARN into partition, service, account and resource.
+	principalARNPattern = regexp.MustCompile(`^arn:([^:]+):(iam|sts)::([0-9]{12}):(.+)$`)
+)
+
+// Request is a call to evaluate against a resource policy.
+type Request struct {
+	// Principal is the ARN of the caller: an IAM role or user, an assumed-role session, or "*" for
+	// an anonymous caller.
+	Principal string
+
+	// Action is the API action, e.g. "codeartifact:GetAuthorizationToken".
+	Action string
+
+	// Resource is the ARN of the target, e.g. the domain or repository ARN.
+	Resource string
+
+	// Context holds the values of the condition keys of the request.
+	Context map[string][]string
+}
+
+// Evaluation is the decision for a request and the statements that produced it.
+type Evaluation struct {
+	Decision Decision
+
+	// Statements lists the SIDs (or "#<index>" for statements without one) of the statements that
+	// match the request with the effect of the decision.
+	Statements []string
+}
+
+// Evaluate decides a request the way IAM evaluates a resource policy on its own: any matching Deny
+// statement wins, otherwise any matching Allow statement allows the request, otherwise it is
+// implicitly denied. Identity policies, SCPs and permission boundaries are not considered, so an
+// account principal ("arn:aws:iam::<account>:root" or "<account>") matches every principal of that
+// account, as if it had been granted the access by its identity policies.
+//
+// Actions match case-insensitively and resources case-sensitively, both with the * and ? wildcards.
+// The String*, Arn*, Bool and Null condition operators are supported, with the IfExists suffix and
+// the ForAnyValue/ForAllValues qualifiers; any other operator is reported as an error.
+func (d *Document) Evaluate(req Request) (Evaluation, error) {
+	context := requestContext(req)
+
+	var allowed, denied []string
+
+	for i, s := range d.Statements {
+		matches, err := s.matches(req, context)
+		if err != nil {
+			return Evaluation{}, fmt.Errorf("statement %s: %w", statementID(s, i), err)
+		}
+
+		if !matches {
+			continue
+		}
+
+		switch s.Effect {
+		case "Deny":
+			denied = append(denied, statementID(s, i))
+		case "Allow":
+			allowed = append(allowed, statementID(s, i))
+		default:
+			return Evaluation{}, fmt.Errorf("statement %s has invalid effect %q", statementID(s, i), s.Effect)
+		}
+	}
+
+	switch {
+	case len(denied) > 0:
+		return Evaluation{Decision: ExplicitDeny, Statements: denied}, nil
+	case len(allowed) > 0:
+		return Evaluation{Decision: Allow, Statements: allowed}, nil
+	default:
+		return Evaluation{Decision: ImplicitDeny}, nil
+	}
+}
+
+// matches reports whether the statement applies to the request, ignoring its effect.
+func (s Statement) matches(req Request, context map[string][]string) (bool, error) {
+	if s.Principals != nil && !principalMatches(s.Principals, req.Principal) {
+		return false, nil
+	}
+
+	if s.NotPrincipals != nil && principalMatches(s.NotPrincipals, req.Principal) {
+		return false, nil
+	}
+
+	if s.Actions != nil && !anyGlob(s.Actions, strings.ToLower(req.Action)) {
+		return false, nil
+	}
+
+	if s.NotActions != nil && anyGlob(s.NotActions, strings.ToLower(req.Action)) {
+		return false, nil
+	}
+
+	if s.Resources != nil && !anyGlob(s.Resources, req.Resource) {
+		return false, nil
+	}
+
+	if s.NotResources != nil && anyGlob(s.NotResources, req.Resource) {
+		return false, nil
+	}
+
+	for operator, keys := range s.Conditions {
+		for key, values := range keys {
+			ok, err := conditionMatches(operator, values, context[strings.ToLower(key)], hasKey(context, key))
+			if err != nil {
+				return false, err
+			}
+
+			if !ok {
+				return false, nil
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// principalMatches reports whether the caller is one of the AWS principals of a statement.
+func principalMatches(principals map[string][]string, caller string) bool {
+	callerAccount, callerRole := parsePrincipal(caller)
+
+	for _, identifier := range principals["AWS"] {
+		switch {
+		case identifier == "*":
+			return true
+		case identifier == caller:
+			return true
+		case accountIDPattern.MatchString(identifier) && identifier == callerAccount:
+			return true
+		}
+
+		account, role := parsePrincipal(identifier)
+		if account == "" || account != callerAccount {
+			continue
+		}
+
+		if strings.HasSuffix(identifier, ":root") {
+			return true
+		}
+
+		// An assumed-role session matches the role it was assumed from
+		if role != "" && role == callerRole {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parsePrincipal returns the account of a principal ARN and, for roles and assumed-role sessions,
+// the role name.
+func parsePrincipal(arn string) (account, role string) {
+	match := principalARNPattern.FindStringSubmatch(arn)
+	if match == nil {
+		return "", ""
+	}
+
+	account, resource := match[3], match[4]
+
+	switch {
+	case match[2] == "iam" && strings.HasPrefix(resource, "role/"):
+		parts := strings.Split(resource, "/")
+		role = parts[len(parts)-1]
+	case match[2] == "sts" && strings.HasPrefix(resource, "assumed-role/"):
+		parts := strings.Split(resource, "/")
+		if len(parts) >= 2 {
+			role = parts[1]
+		}
+	}
+
+	return account, role
+}
+
+// requestContext returns the context of the request with the principal keys filled in.
+func requestContext(req Request) map[string][]string {
+	context := make(map[string][]string, len(req.Context)+2)
+	for key, values := range req.Context {
+		context[strings.ToLower(key)] = values
+	}
+
+	account, role := parsePrincipal(req.Principal)
+	if account == "" {
+		return context
+	}
+
+	principalARN := req.Principal
+	if strings.Contains(req.Principal, ":assumed-role/") {
+		partition := principalARNPattern.FindStringSubmatch(req.Principal)[1]
+		principalARN = fmt.Sprintf("arn:%s:iam::%s:role/%s", partition, account, role)
+	}
+
+	if _, ok := context[strings.ToLower(PrincipalARNKey)]; !ok {
+		context[strings.ToLower(PrincipalARNKey)] = []string{principalARN}
+	}
+
+	if _, ok := context[strings.ToLower(PrincipalAccountKey)]; !ok {
+		context[strings.ToLower(PrincipalAccountKey)] = []string{account}
+	}
+
+	return context
+}
+
+// hasKey reports whether the context sets a condition key. Condition keys are case-insensitive.
+func hasKey(context map[string][]string, key string) bool {
+	_, ok := context[strings.ToLower(key)]
+	return ok
+}
+
+// conditionMatches evaluates one condition key. The request matches when any of its values matches
+// any of the policy values, unless a set qualifier says otherwise.
+func conditionMatches(operator string, policyValues, requestValues []string, present bool) (bool, error) {
+	qualifier := ""
+	if i := strings.Index(operator, ":"); i >= 0 {
+		qualifier, operator = operator[:i], operator[i+1:]
+	}
+
+	ifExists := strings.HasSuffix(operator, ifExistsSuffix)
+	operator = strings.TrimSuffix(operator, ifExistsSuffix)
+
+	if operator == "Null" {
+		return len(policyValues) > 0 && (policyValues[0] == "true") == !present, nil
+	}
+
+	compare, negated, err := comparator(operator)
+	if err != nil {
+		return false, err
+	}
+
+	if !present {
+		// A missing key matches negated operators, IfExists operators and ForAllValues
+		return negated || ifExists || qualifier == "ForAllValues", nil
+	}
+
+	matchesAny := func(value string) bool {
+		for _, policyValue := range policyValues {
+			if compare(policyValue, value) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	switch qualifier {
+	case "ForAllValues":
+		for _, value := range requestValues {
+			if matchesAny(value) == negated {
+				return false, nil
+			}
+		}
+
+		return true, nil
+	case "", "ForAnyValue":
+		for _, value := range requestValues {
+			if matchesAny(value) {
+				return !negated, nil
+			}
+		}
+
+		return negated, nil
+	default:
+		return false, fmt.Errorf("unsupported condition qualifier %q", qualifier)
+	}
+}
+
+// comparator returns the value comparison of a condition operator and whether it is negated.
+func comparator(operator string) (func(policyValue, value string) bool, bool, error) {
+	equals := func(policyValue, value string) bool { return policyValue == value }
+	equalsIgnoreCase := strings.EqualFold
+	like := func(policyValue, value string) bool { return glob(policyValue, value) }
+
+	switch operator {
+	case "StringEquals", "ArnEquals", "Bool":
+		return equals, false, nil
+	case "StringNotEquals", "ArnNotEquals":
+		return equals, true, nil
+	case "StringEqualsIgnoreCase":
+		return equalsIgnoreCase, false, nil
+	case "StringNotEqualsIgnoreCase":
+		return equalsIgnoreCase, true, nil
+	case "StringLike", "ArnLike":
+		return like, false, nil
+	case "StringNotLike", "ArnNotLike":
+		return like, true, nil
+	default:
+		return nil, false, fmt.Errorf("unsupported condition operator %q", operator)
+	}
+}
+
+// anyGlob reports whether value matches any of the patterns.
+func anyGlob(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if glob(pattern, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// glob matches value against an IAM pattern, where * matches any run of characters and ? any one.
+func glob(pattern, value string) bool {
+	if !strings.ContainsAny(pattern, "*?") {
+		return pattern == value
+	}
+
+	var expr strings.Builder
+
+	expr.WriteString("^")
+
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+
+	expr.WriteString("$")
+
+	return regexp.MustCompile(expr.String()).MatchString(value)
+}
+
+// statementID identifies a statement in evaluation results.
+func statementID(s Statement, index int) string {
+	if s.Sid != "" {
+		return s.Sid
+	}
+
+	return fmt.Sprintf("#%d", index)
+}
@@ -0,0 +1,263 @@
+package iampolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testDomainARN  = "arn:aws:codeartifact:us-west-2:111122223333:domain/example"
+	testRoleARN    = "arn:aws:iam::111122223333:role/ci-publisher"
+	testSessionARN = "arn:aws:sts::111122223333:assumed-role/ci-publisher/session-1"
+)
+
+func TestEvaluate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		policy     string
+		request    Request
+		want       Decision
+		statements []string
+	}{
+		{
+			name: "deny overrides allow",
+			policy: `{"Version": "2012-10-17", "Statement": [
+				{"Sid": "Allow", "Effect": "Allow", "Principal": "*", "Action": "codeartifact:*", "Resource": "*"},
+				{"Sid": "Deny", "Effect": "Deny", "Principal": "*", "Action": "codeartifact:DeleteDomain", "Resource": "*"}
+			]}`,
+			request:    Request{Principal: testRoleARN, Action: "codeartifact:DeleteDomain", Resource: testDomainARN},
+			want:       ExplicitDeny,
+			statements: []string{"Deny"},
+		},
+		{
+			name: "allow when no deny matches",
+			policy: `{"Version": "2012-10-17", "Statement": [
+				{"Sid": "Allow", "Effect": "Allow", "Principal": "*", "Action": "codeartifact:*", "Resource": "*"},
+				{"Sid": "Deny", "Effect": "Deny", "Principal": "*", "Action": "codeartifact:DeleteDomain", "Resource": "*"}
+			]}`,
+			request:    Request{Principal: testRoleARN, Action: "codeartifact:DescribeDomain", Resource: testDomainARN},
+			want:       Allow,
+			statements: []string{"Allow"},
+		},
+		{
+			name: "no matching statement is an implicit deny",
+			policy: `{"Version": "2012-10-17", "Statement": [
+				{"Effect": "Allow", "Principal": "*", "Action": "codeartifact:ReadFromRepository", "Resource": "*"}
+			]}`,
+			request: Request{Principal: testRoleARN, Action: "codeartifact:PublishPackageVersion", Resource: testDomainARN},
+			want:    ImplicitDeny,
+		},
+		{
+			name: "actions match case-insensitively",
+			policy: `{"Version": "2012-10-17", "Statement": [
+				{"Effect": "Allow", "Principal": "*", "Action": "CodeArtifact:GetAuthorizationToken", "Resource": "*"}
+			]}`,
+			request:    Request{Principal: testRoleARN, Action: "codeartifact:getauthorizationtoken", Resource: testDomainARN},
+			want:       Allow,
+			statements: []string{"#0"},
+		},
+		{
+			name: "not action excludes the listed actions",
+			policy: `{"Version": "2012-10-17", "Statement": [
+				{"Effect": "Allow", "Principal": "*", "NotAction": "codeartifact:Delete*", "Resource": "*"}
+			]}`,
+			request: Request{Principal: testRoleARN, Action: "codeartifact:DeleteDomain", Resource: testDomainARN},
+			want:    ImplicitDeny,
+		},
+		{
+			name: "not action matches the other actions",
+			policy: `{"Version": "2012-10-17", "Statement": [
+				{"Effect": "Allow", "Principal": "*", "NotAction": "codeartifact:Delete*", "Resource": "*"}
+			]}`,
+			request:    Request{Principal: testRoleARN, Action: "codeartifact:DescribeDomain", Resource: testDomainARN},
+			want:       Allow,
+			statements: []string{"#0"},
+		},
+		{
+			name: "not resource excludes the listed resources",
+			policy: `{"Version": "2012-10-17", "Statement": [
+				{"Effect": "Allow", "Principal": "*", "Action": "codeartifact:*", "NotResource": "arn:aws:codeartifact:*:*:domain/example"}
+			]}`,
+			request: Request{Principal: testRoleARN, Action: "codeartifact:DescribeDomain", Resource: testDomainARN},
+			want:    ImplicitDeny,
+		},
+		{
+			name: "not resource matches the other resources",
+			policy: `{"Version": "2012-10-17", "Statement": [
+				{"Effect": "Allow", "Principal": "*", "Action": "codeartifact:*", "NotResource": "arn:aws:codeartifact:*:*:domain/other"}
+			]}`,
+			request:    Request{Principal: testRoleARN, Action: "codeartifact:DescribeDomain", Resource: testDomainARN},
+			want:       Allow,
+			statements: []string{"#0"},
+		},
+		{
+			name: "string like matches wildcards",
+			policy: `{"Version": "2012-10-17", "Statement": [
+				{"Effect": "Allow", "Principal": "*", "Action": "codeartifact:*", "Resource": "*",
+				 "Condition": {"StringLike": {"aws:PrincipalArn": "arn:aws:iam::111122223333:role/ci-*"}}}
+			]}`,
+			request:    Request{Principal: testRoleARN, Action: "codeartifact:DescribeDomain", Resource: testDomainARN},
+			want:       Allow,
+			statements: []string{"#0"},
+		},
+		{
+			name: "string like rejects values outside the pattern",
+			policy: `{"Version": "2012-10-17", "Statement": [
+				{"Effect": "Allow", "Principal": "*", "Action": "codeartifact:*", "Resource": "*",
+				 "Condition": {"StringLike": {"aws:PrincipalArn": "arn:aws:iam::111122223333:role/ci-?"}}}
+			]}`,
+			request: Request{Principal: testRoleARN, Action: "codeartifact:DescribeDomain", Resource: testDomainARN},
+			want:    ImplicitDeny,
+		},
+		{
+			name: "string equals does not match a missing key",
+			policy: `{"Version": "2012-10-17", "Statement": [
+				{"Effect": "Allow", "Principal": "*", "Action": "codeartifact:*", "Resource": "*",
+				 "Condition": {"StringEquals": {"aws:SourceVpc": "vpc-1234"}}}
+			]}`,
+			request: Request{Principal: testRoleARN, Action: "codeartifact:DescribeDomain", Resource: testDomainARN},
+			want:    ImplicitDeny,
+		},
+		{
+			name: "string not equals matches a missing key",
+			policy: `{"Version": "2012-10-17", "Statement": [
+				{"Effect": "Deny", "Principal": "*", "Action": "codeartifact:*", "Resource": "*",
+				 "Condition": {"StringNotEquals": {"aws:SourceVpc": "vpc-1234"}}}
+			]}`,
+			request:    Request{Principal: testRoleARN, Action: "codeartifact:DescribeDomain", Resource: testDomainARN},
+			want:       ExplicitDeny,
+			statements: []string{"#0"},
+		},
+		{
+			name: "string not equals does not match the listed value",
+			policy: `{"Version": "2012-10-17", "Statement": [
+				{"Effect": "Deny", "Principal": "*", "Action": "codeartifact:*", "Resource": "*",
+				 "Condition": {"StringNotEquals": {"aws:SourceVpc": "vpc-1234"}}}
+			]}`,
+			request: Request{
+				Principal: testRoleARN,
+				Action:    "codeartifact:DescribeDomain",
+				Resource:  testDomainARN,
+				Context:   map[string][]string{"aws:SourceVpc": {"vpc-1234"}},
+			},
+			want: ImplicitDeny,
+		},
+		{
+			name: "if exists matches a missing key",
+			policy: `{"Version": "2012-10-17", "Statement": [
+				{"Effect": "Allow", "Principal": "*", "Action": "codeartifact:*", "Resource": "*",
+				 "Condition": {"StringEqualsIfExists": {"aws:SourceVpc": "vpc-1234"}}}
+			]}`,
+			request:    Request{Principal: testRoleARN, Action: "codeartifact:DescribeDomain", Resource: testDomainARN},
+			want:       Allow,
+			statements: []string{"#0"},
+		},
+		{
+			name: "for all values matches a missing key",
+			policy: `{"Version": "2012-10-17", "Statement": [
+				{"Effect": "Allow", "Principal": "*", "Action": "codeartifact:*", "Resource": "*",
+				 "Condition": {"ForAllValues:StringEquals": {"aws:TagKeys": ["team"]}}}
+			]}`,
+			request:    Request{Principal: testRoleARN, Action: "codeartifact:DescribeDomain", Resource: testDomainARN},
+			want:       Allow,
+			statements: []string{"#0"},
+		},
+		{
+			name: "for all values rejects a value outside the set",
+			policy: `{"Version": "2012-10-17", "Statement": [
+				{"Effect": "Allow", "Principal": "*", "Action": "codeartifact:*", "Resource": "*",
+				 "Condition": {"ForAllValues:StringEquals": {"aws:TagKeys": ["team"]}}}
+			]}`,
+			request: Request{
+				Principal: testRoleARN,
+				Action:    "codeartifact:DescribeDomain",
+				Resource:  testDomainARN,
+				Context:   map[string][]string{"aws:TagKeys": {"team", "owner"}},
+			},
+			want: ImplicitDeny,
+		},
+		{
+			name: "assumed-role session matches its role",
+			policy: `{"Version": "2012-10-17", "Statement": [
+				{"Effect": "Allow", "Principal": {"AWS": "arn:aws:iam::111122223333:role/ci-publisher"}, "Action": "codeartifact:*", "Resource": "*"}
+			]}`,
+			request:    Request{Principal: testSessionARN, Action: "codeartifact:DescribeDomain", Resource: testDomainARN},
+			want:       Allow,
+			statements: []string{"#0"},
+		},
+		{
+			name: "assumed-role session does not match another role",
+			policy: `{"Version": "2012-10-17", "Statement": [
+				{"Effect": "Allow", "Principal": {"AWS": "arn:aws:iam::111122223333:role/ci-reader"}, "Action": "codeartifact:*", "Resource": "*"}
+			]}`,
+			request: Request{Principal: testSessionARN, Action: "codeartifact:DescribeDomain", Resource: testDomainARN},
+			want:    ImplicitDeny,
+		},
+		{
+			name: "assumed-role session principal arn is its role",
+			policy: `{"Version": "2012-10-17", "Statement": [
+				{"Effect": "Allow", "Principal": "*", "Action": "codeartifact:*", "Resource": "*",
+				 "Condition": {"ArnEquals": {"aws:PrincipalArn": "arn:aws:iam::111122223333:role/ci-publisher"}}}
+			]}`,
+			request:    Request{Principal: testSessionARN, Action: "codeartifact:DescribeDomain", Resource: testDomainARN},
+			want:       Allow,
+			statements: []string{"#0"},
+		},
+		{
+			name: "account root matches every principal of the account",
+			policy: `{"Version": "2012-10-17", "Statement": [
+				{"Effect": "Allow", "Principal": {"AWS": "arn:aws:iam::111122223333:root"}, "Action": "codeartifact:*", "Resource": "*"}
+			]}`,
+			request:    Request{Principal: testSessionARN, Action: "codeartifact:DescribeDomain", Resource: testDomainARN},
+			want:       Allow,
+			statements: []string{"#0"},
+		},
+		{
+			name: "account id matches every principal of the account",
+			policy: `{"Version": "2012-10-17", "Statement": [
+				{"Effect": "Allow", "Principal": {"AWS": "111122223333"}, "Action": "codeartifact:*", "Resource": "*"}
+			]}`,
+			request:    Request{Principal: testRoleARN, Action: "codeartifact:DescribeDomain", Resource: testDomainARN},
+			want:       Allow,
+			statements: []string{"#0"},
+		},
+		{
+			name: "account root does not match another account",
+			policy: `{"Version": "2012-10-17", "Statement": [
+				{"Effect": "Allow", "Principal": {"AWS": "arn:aws:iam::444455556666:root"}, "Action": "codeartifact:*", "Resource": "*"}
+			]}`,
+			request: Request{Principal: testRoleARN, Action: "codeartifact:DescribeDomain", Resource: testDomainARN},
+			want:    ImplicitDeny,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			doc, err := Parse(tt.policy)
+			require.NoError(t, err, "Failed to parse the policy")
+
+			got, err := doc.Evaluate(tt.request)
+			require.NoError(t, err, "Failed to evaluate the request")
+			require.Equal(t, tt.want, got.Decision, "Unexpected decision")
+			require.Equal(t, tt.statements, got.Statements, "Unexpected deciding statements")
+		})
+	}
+}
+
+func TestEvaluateWhenOperatorIsUnsupported(t *testing.T) {
+	t.Parallel()
+
+	doc, err := Parse(`{"Version": "2012-10-17", "Statement": [
+		{"Effect": "Allow", "Principal": "*", "Action": "codeartifact:*", "Resource": "*",
+		 "Condition": {"NumericLessThan": {"aws:MultiFactorAuthAge": "3600"}}}
+	]}`)
+	require.NoError(t, err, "Failed to parse the policy")
+
+	_, err = doc.Evaluate(Request{Principal: testRoleARN, Action: "codeartifact:DescribeDomain", Resource: testDomainARN})
+	require.ErrorContains(t, err, "unsupported condition operator")
+}
@@ -0,0 +1,290 @@
+// Package invariant checks properties that every module and example of the repository must hold,
+// discovering them so that a new module or example is covered as soon as it is committed.
+package invariant
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/contract"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/harness"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/plan"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/repo"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	// disabledFixture is the fixture every example provides to plan its module disabled.
+	disabledFixture = "disabled"
+
+	// enabledVariable is the variable that turns every module of the repository on and off.
+	enabledVariable = "is_enabled"
+
+	// placeholderString is the value given to required string variables without a configured value.
+	placeholderString = "disabled"
+)
+
+// offlineDataSources are the data sources computed from the provider configuration alone. A
+// disabled configuration may read them, since they call no API and need no credentials.
+var offlineDataSources = map[string]bool{
+	"aws_iam_policy_document": true,
+	"aws_partition":           true,
+	"aws_region":              true,
+}
+
+// DisabledConfig controls the disabled-mode suite.
+type DisabledConfig struct {
+	// Vars holds values for the required variables of a module, keyed by module name. Required
+	// variables without a value get a placeholder of their type, e.g. "disabled" for a string.
+	Vars map[string]map[string]interface{}
+
+	// Skip maps "modules/<module>" or "examples/<example path>" to the reason it cannot be checked.
+	Skip map[string]string
+}
+
+// RunDisabled plans every module with is_enabled = false, and every example with its disabled.tfvars
+// fixture or, when it has none, with is_enabled = false, in parallel subtests. Each plan must hold
+// the disabled-mode invariants of RequireDisabledPlan. Module outputs are checked against their
+// contract; example outputs that a module contract declares follow it, and the others must be null,
+// false or empty. A module without a contract fails its subtest.
+func RunDisabled(t *testing.T, cfg DisabledConfig) {
+	t.Helper()
+
+	dirs, err := repo.NewTFSourcesDir()
+	require.NoError(t, err, "Failed to get Terraform sources directory")
+
+	modules, err := dirs.ListModules()
+	require.NoError(t, err, "Failed to discover modules")
+	require.NotEmpty(t, modules, "No modules found")
+
+	examples, err := dirs.ListAllExamples()
+	require.NoError(t, err, "Failed to discover examples")
+
+	for _, module := range modules {
+		module := module
+
+		t.Run("modules/"+module, func(t *testing.T) {
+			t.Parallel()
+			skipIfConfigured(t, cfg, "modules/"+module)
+			planDisabledModule(t, dirs, module, cfg.Vars[module])
+		})
+	}
+
+	for _, example := range examples {
+		example := example
+
+		t.Run("examples/"+example.Path, func(t *testing.T) {
+			t.Parallel()
+			skipIfConfigured(t, cfg, "examples/"+example.Path)
+			planDisabledExample(t, example)
+		})
+	}
+}
+
+// RequireDisabledPlan fails the test if the plan has any managed resource or reads a data source
+// that needs credentials, or if an output does not hold its disabled value. outputRule returns the
+// contract of an output, when one applies; outputs without one must be null, false or empty.
+func RequireDisabledPlan(t *testing.T, p *plan.Plan, outputRule func(name string) (contract.Output, bool)) {
+	t.Helper()
+
+	var managed []string
+	for _, rc := range p.ManagedResourceChanges() {
+		managed = append(managed, rc.Address)
+	}
+
+	require.Emptyf(t, managed, "A disabled configuration should plan no managed resources")
+
+	require.Emptyf(t, credentialedDataSources(p),
+		"A disabled configuration should read no data source that needs credentials; gate them with count = var.is_enabled ? 1 : 0")
+
+	for _, name := range sortedOutputNames(p) {
+		change := p.OutputChanges[name]
+
+		unknown, _ := change.AfterUnknown.(bool)
+		require.Falsef(t, unknown, "Output %q of a disabled configuration should be known at plan time", name)
+
+		if output, ok := outputRule(name); ok {
+			require.NoErrorf(t, output.Disabled.Check(change.After), "Output %q should hold its disabled value", name)
+			continue
+		}
+
+		require.Truef(t, isEmptyValue(change.After), "Output %q should be null, false or empty, got %v", name, change.After)
+	}
+
+	t.Logf("✅ Disabled plan holds the invariants: no managed resources, no credentialed data sources, %d outputs checked", len(p.OutputChanges))
+}
+
+// planDisabledModule plans a module with is_enabled = false and checks its plan and output contract.
+func planDisabledModule(t *testing.T, dirs *repo.TFSourcesDir, module string, configured map[string]interface{}) {
+	moduleContract, ok := contract.ForModule(module)
+	require.Truef(t, ok, "Module %s has no output contract; add one to pkg/contract", module)
+
+	vars, err := requiredVars(dirs.GetModulesDir(module), configured)
+	require.NoError(t, err, "Failed to resolve the required variables of module %s", module)
+
+	vars[enabledVariable] = false
+
+	terraformOptions := helper.NewTerraformOptions(t, helper.ModuleSource(dirs.GetModulesDir(module)),
+		harness.Load(t).TerraformOption(),
+		helper.WithWorkspaceCopy(),
+		helper.WithVars(vars),
+		helper.WithNoColor(),
+	)
+
+	t.Logf("🔍 Terraform Module Directory: %s", terraformOptions.TerraformDir)
+
+	tfPlan := plan.InitAndPlan(t, terraformOptions)
+
+	RequireDisabledPlan(t, tfPlan, moduleContract.Output)
+	contract.RequirePlanned(t, moduleContract, tfPlan, contract.ModeDisabled)
+}
+
+// planDisabledExample plans an example with its disabled fixture, or with is_enabled = false when it
+// has none, and checks its plan. Outputs the contract of the example's module declares follow that
+// contract.
+func planDisabledExample(t *testing.T, example repo.Example) {
+	opts := []helper.SetupOption{helper.WithWorkspaceCopy(), helper.WithNoColor()}
+
+	fixture, ok := example.Fixture(disabledFixture)
+	if ok {
+		opts = append(opts, helper.WithVarFiles(fixture.VarFile()))
+	} else {
+		variables, err := repo.ParseVariables(example.Dir)
+		require.NoError(t, err, "Failed to parse the variables of example %s", example.Path)
+
+		_, declared := variables[enabledVariable]
+		require.Truef(t, declared, "Example %s has neither fixtures/%s.tfvars nor an %s variable", example.Path, disabledFixture, enabledVariable)
+
+		opts = append(opts, helper.WithVars(map[string]interface{}{enabledVariable: false}))
+	}
+
+	terraformOptions := helper.NewTerraformOptions(t, helper.ExampleSource(example.Path), opts...)
+
+	t.Logf("🔍 Terraform Example Directory: %s", terraformOptions.TerraformDir)
+
+	tfPlan := plan.InitAndPlan(t, terraformOptions)
+
+	moduleContract, _ := contract.ForModule(example.Module)
+	RequireDisabledPlan(t, tfPlan, moduleContract.Output)
+}
+
+// credentialedDataSources returns the addresses of the data sources the plan read, or will read on
+// apply, that are not offline.
+func credentialedDataSources(p *plan.Plan) []string {
+	var addresses []string
+
+	if p.PriorState != nil {
+		for _, r := range p.PriorState.Values.RootModule.AllResources() {
+			if r.Mode == plan.ModeData && !offlineDataSources[r.Type] {
+				addresses = append(addresses, r.Address)
+			}
+		}
+	}
+
+	for _, rc := range p.ResourceChanges {
+		if rc.Mode == plan.ModeData && !offlineDataSources[rc.Type] {
+			addresses = append(addresses, rc.Address)
+		}
+	}
+
+	sort.Strings(addresses)
+
+	return addresses
+}
+
+// requiredVars returns values for the required variables of a module: the configured ones, and a
+// placeholder of its type for every other.
+func requiredVars(moduleDir string, configured map[string]interface{}) (map[string]interface{}, error) {
+	variables, err := repo.ParseVariables(moduleDir)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := make(map[string]interface{}, len(variables)+1)
+
+	for name, value := range configured {
+		if _, ok := variables[name]; !ok {
+			return nil, fmt.Errorf("variable %q is not declared", name)
+		}
+
+		vars[name] = value
+	}
+
+	for name, variable := range variables {
+		if _, ok := vars[name]; ok || !variable.Required() {
+			continue
+		}
+
+		vars[name] = placeholder(variable.Type)
+	}
+
+	return vars, nil
+}
+
+// placeholder returns a value of the given type: "disabled", 1, false, an empty collection, or an
+// object with a placeholder for each required attribute.
+func placeholder(typ cty.Type) interface{} {
+	switch {
+	case typ == cty.Number:
+		return 1
+	case typ == cty.Bool:
+		return false
+	case typ.IsListType(), typ.IsSetType(), typ.IsTupleType():
+		return []interface{}{}
+	case typ.IsMapType():
+		return map[string]interface{}{}
+	case typ.IsObjectType():
+		object := map[string]interface{}{}
+
+		for name, attribute := range typ.AttributeTypes() {
+			if !typ.AttributeOptional(name) {
+				object[name] = placeholder(attribute)
+			}
+		}
+
+		return object
+	default:
+		return placeholderString
+	}
+}
+
+// isEmptyValue reports whether a decoded JSON value is null, false, an empty string or an empty
+// collection.
+func isEmptyValue(v interface{}) bool {
+	switch typed := v.(type) {
+	case nil:
+		return true
+	case bool:
+		return !typed
+	case string:
+		return typed == ""
+	case []interface{}:
+		return len(typed) == 0
+	case map[string]interface{}:
+		return len(typed) == 0
+	default:
+		return false
+	}
+}
+
+// sortedOutputNames returns the names of the planned outputs in order.
+func sortedOutputNames(p *plan.Plan) []string {
+	names := make([]string, 0, len(p.OutputChanges))
+	for name := range p.OutputChanges {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// skipIfConfigured skips the subtest when the configuration lists a reason for it.
+func skipIfConfigured(t *testing.T, cfg DisabledConfig, id string) {
+	if reason, ok := cfg.Skip[id]; ok {
+		t.Skipf("⏭️ Skipping %s: %s", id, reason)
+	}
+}
@@ -0,0 +1,180 @@
+package lint
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/repo"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// maxSuggestionDistance is the largest edit distance for which a declared variable is suggested
+// in place of an unknown one.
+const maxSuggestionDistance = 3
+
+// Finding is a problem found in a fixture, located at a line of the fixture file.
+type Finding struct {
+	File    string // The fixture path, relative to the repository root.
+	Line    int    // The line of the offending assignment; 1 for missing variables.
+	Message string
+}
+
+// String renders the finding as "file:line: message", the format editors and CI annotate.
+func (f Finding) String() string {
+	return fmt.Sprintf("%s:%d: %s", f.File, f.Line, f.Message)
+}
+
+// RequireValidFixtures checks every fixture of every example of the module against the variables
+// the example declares, in one subtest per fixture, and reports each finding as a test error.
+func RequireValidFixtures(t *testing.T, moduleName string) {
+	dirs, err := repo.NewTFSourcesDir()
+	require.NoError(t, err, "Failed to get Terraform sources directory")
+
+	examples, err := dirs.ListExamples(moduleName)
+	require.NoError(t, err, "Failed to discover examples of module %s", moduleName)
+
+	for _, example := range examples {
+		if len(example.Fixtures) == 0 {
+			continue
+		}
+
+		variables, err := repo.ParseVariables(example.Dir)
+		require.NoError(t, err, "Failed to parse the variables of example %s", example.Path)
+
+		for _, fixture := range example.Fixtures {
+			t.Run(example.ID()+"/"+fixture.Name, func(t *testing.T) {
+				findings, err := Fixture(dirs.GetRootDir(), fixture.Path, variables)
+				require.NoError(t, err, "Failed to lint fixture %s", fixture.Path)
+
+				for _, finding := range findings {
+					t.Errorf("❌ %s", finding)
+				}
+
+				if len(findings) == 0 {
+					t.Logf("✅ %s/%s matches the variables of the example", example.Path, fixture.VarFile())
+				}
+			})
+		}
+	}
+}
+
+// Fixture checks a .tfvars file against the variables of its configuration. It reports
+// assignments to undeclared variables, values that do not convert to the declared type or that
+// Terraform cannot evaluate, and required variables the fixture leaves without a value. Paths in
+// the findings are relative to rootDir.
+func Fixture(rootDir, fixturePath string, variables map[string]repo.Variable) ([]Finding, error) {
+	assignments, err := repo.ParseVarFile(fixturePath)
+	if err != nil {
+		return nil, err
+	}
+
+	file := fixturePath
+	if rel, err := filepath.Rel(rootDir, fixturePath); err == nil {
+		file = rel
+	}
+
+	var findings []Finding
+
+	for name, assignment := range assignments {
+		line := assignment.Range.Start.Line
+
+		variable, declared := variables[name]
+		if !declared {
+			message := fmt.Sprintf("variable %q is not declared by the example", name)
+			if suggestion := closestName(name, variables); suggestion != "" {
+				message += fmt.Sprintf("; did you mean %q?", suggestion)
+			}
+
+			findings = append(findings, Finding{File: file, Line: line, Message: message})
+
+			continue
+		}
+
+		value, err := assignment.Value()
+		if err != nil {
+			findings = append(findings, Finding{File: file, Line: line, Message: err.Error()})
+			continue
+		}
+
+		if _, err := convert.Convert(value, variable.Type); err != nil {
+			findings = append(findings, Finding{
+				File:    file,
+				Line:    line,
+				Message: fmt.Sprintf("variable %q expects %s: %v", name, typeexpr.TypeString(variable.Type), err),
+			})
+		}
+	}
+
+	for name, variable := range variables {
+		if _, assigned := assignments[name]; assigned || !variable.Required() {
+			continue
+		}
+
+		declaredAt := variable.Range.Filename
+		if rel, err := filepath.Rel(rootDir, declaredAt); err == nil {
+			declaredAt = rel
+		}
+
+		findings = append(findings, Finding{
+			File:    file,
+			Line:    1,
+			Message: fmt.Sprintf("required variable %q (%s:%d) has no value", name, declaredAt, variable.Range.Start.Line),
+		})
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Line != findings[j].Line {
+			return findings[i].Line < findings[j].Line
+		}
+
+		return findings[i].Message < findings[j].Message
+	})
+
+	return findings, nil
+}
+
+// closestName returns the declared variable closest to name, if any is close enough to be a typo.
+func closestName(name string, variables map[string]repo.Variable) string {
+	best, bestDistance := "", maxSuggestionDistance+1
+
+	for candidate := range variables {
+		distance := editDistance(strings.ToLower(name), strings.ToLower(candidate))
+		if distance < bestDistance || (distance == bestDistance && candidate < best) {
+			best, bestDistance = candidate, distance
+		}
+	}
+
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
@@ -0,0 +1,278 @@
+package naming
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// SeedEnvVar fixes the seed of the naming service so the names of a failed run can be reproduced.
+const SeedEnvVar = "TF_TEST_NAME_SEED"
+
+const (
+	// runIDLength is the length of the per-run random component shared by every name of a run.
+	runIDLength = 4
+
+	// uniqueLength is the length of the per-name component derived from the test, base and call order.
+	uniqueLength = 6
+
+	// alphabet is the character set of generated components, valid in every resource type.
+	alphabet = "0123456789abcdefghijklmnopqrstuvwxyz"
+)
+
+// ResourceType describes the naming rules of an AWS resource type.
+type ResourceType struct {
+	Name      string         // A human-readable name of the resource type, e.g. "S3 bucket".
+	Prefix    string         // A fixed prefix every name must start with, e.g. "alias/".
+	MaxLength int            // The maximum length of the full name, prefix included.
+	Lowercase bool           // Whether upper-case letters are forbidden.
+	Invalid   *regexp.Regexp // The characters that are replaced with a hyphen.
+	Pattern   *regexp.Regexp // The pattern the full name must match.
+}
+
+// Resource types with the naming rules documented by AWS.
+var (
+	// Generic fits the strictest common rules: lower-case letters, digits and hyphens, 63 characters.
+	Generic = ResourceType{
+		Name:      "resource",
+		MaxLength: 63,
+		Lowercase: true,
+		Invalid:   regexp.MustCompile(`[^a-z0-9-]+`),
+		Pattern:   regexp.MustCompile(`^[a-z][a-z0-9-]*[a-z0-9]$`),
+	}
+
+	// S3Bucket names are 3 to 63 lower-case letters, digits and hyphens. Dots are valid but
+	// break virtual-hosted TLS, so they are replaced.
+	S3Bucket = ResourceType{
+		Name:      "S3 bucket",
+		MaxLength: 63,
+		Lowercase: true,
+		Invalid:   regexp.MustCompile(`[^a-z0-9-]+`),
+		Pattern:   regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,61}[a-z0-9]$`),
+	}
+
+	// CodeArtifactDomain names are 2 to 50 characters, starting with a lower-case letter.
+	CodeArtifactDomain = ResourceType{
+		Name:      "CodeArtifact domain",
+		MaxLength: 50,
+		Lowercase: true,
+		Invalid:   regexp.MustCompile(`[^a-z0-9-]+`),
+		Pattern:   regexp.MustCompile(`^[a-z][a-z0-9\-]{0,48}[a-z0-9]$`),
+	}
+
+	// CodeArtifactRepository names are 2 to 100 letters, digits, dots, underscores and hyphens.
+	CodeArtifactRepository = ResourceType{
+		Name:      "CodeArtifact repository",
+		MaxLength: 100,
+		Invalid:   regexp.MustCompile(`[^A-Za-z0-9._-]+`),
+		Pattern:   regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._\-]{1,99}$`),
+	}
+
+	// IAMRole names are up to 64 alphanumeric characters and +=,.@_-.
+	IAMRole = ResourceType{
+		Name:      "IAM role",
+		MaxLength: 64,
+		Invalid:   regexp.MustCompile(`[^\w+=,.@-]+`),
+		Pattern:   regexp.MustCompile(`^[\w+=,.@-]{1,64}$`),
+	}
+
+	// KMSAlias names start with "alias/" and hold up to 256 letters, digits, slashes, underscores and
+	// hyphens. The name part must not start with a slash.
+	KMSAlias = ResourceType{
+		Name:      "KMS alias",
+		Prefix:    "alias/",
+		MaxLength: 256,
+		Invalid:   regexp.MustCompile(`[^A-Za-z0-9/_-]+`),
+		Pattern:   regexp.MustCompile(`^alias/[A-Za-z0-9_-][A-Za-z0-9/_-]*$`),
+	}
+
+	// LogGroup names are up to 512 letters, digits and ._-/#.
+	LogGroup = ResourceType{
+		Name:      "CloudWatch log group",
+		MaxLength: 512,
+		Invalid:   regexp.MustCompile(`[^A-Za-z0-9._/#-]+`),
+		Pattern:   regexp.MustCompile(`^[A-Za-z0-9._/#-]{1,512}$`),
+	}
+)
+
+// Namer generates unique, rule-compliant names. Names depend only on the seed, the test name, the
+// resource type, the base name and how many names the test requested for them before, so the same
+// seed reproduces the same names regardless of how parallel tests are scheduled.
+type Namer struct {
+	seed  int64
+	runID string
+
+	mu       sync.Mutex
+	counters map[string]int
+}
+
+var (
+	defaultOnce  sync.Once
+	defaultNamer *Namer
+	defaultErr   error
+)
+
+// New returns a namer for the given seed.
+func New(seed int64) *Namer {
+	return &Namer{
+		seed:     seed,
+		runID:    encode(digest(seed, "run"), runIDLength),
+		counters: map[string]int{},
+	}
+}
+
+// Default returns the namer of the `go test` process. Its seed is read from TF_TEST_NAME_SEED, or
+// drawn at random; every generated name is logged with it so a failing run can be reproduced.
+func Default(t *testing.T) *Namer {
+	n, err := DefaultE()
+	if err != nil {
+		t.Fatalf("❌ %v", err)
+	}
+
+	return n
+}
+
+// DefaultE returns the namer of the `go test` process like Default, returning an invalid seed as an error.
+func DefaultE() (*Namer, error) {
+	defaultOnce.Do(func() {
+		seed, err := seedFromEnv()
+		if err != nil {
+			defaultErr = fmt.Errorf("invalid %s: %w", SeedEnvVar, err)
+			return
+		}
+
+		defaultNamer = New(seed)
+	})
+
+	return defaultNamer, defaultErr
+}
+
+// Name returns a unique name of the given type for the test, using the default namer.
+func Name(t *testing.T, rt ResourceType, base string) string {
+	t.Helper()
+
+	return Default(t).Name(t, rt, base)
+}
+
+// Seed returns the seed of the namer.
+func (n *Namer) Seed() int64 {
+	return n.seed
+}
+
+// RunID returns the random component shared by every name of the run.
+func (n *Namer) RunID() string {
+	return n.runID
+}
+
+// Name returns a unique name of the given type for the test, failing the test when the base name
+// cannot be turned into a valid name.
+func (n *Namer) Name(t *testing.T, rt ResourceType, base string) string {
+	t.Helper()
+
+	name, err := n.NameFor(t.Name(), rt, base)
+	if err != nil {
+		t.Fatalf("❌ %v", err)
+	}
+
+	t.Logf("🏷️ Generated %s name %s (set %s=%d to reproduce)", rt.Name, name, SeedEnvVar, n.seed)
+
+	return name
+}
+
+// NameFor returns a unique name of the given type for the named owner (usually a test name).
+// The base is sanitized and truncated to the type's rules, then suffixed with the run ID and a
+// component derived from the owner, the base and the number of earlier calls with both.
+func (n *Namer) NameFor(owner string, rt ResourceType, base string) (string, error) {
+	key := rt.Name + "\x00" + owner + "\x00" + base
+
+	n.mu.Lock()
+	call := n.counters[key]
+	n.counters[key]++
+	n.mu.Unlock()
+
+	unique := encode(digest(n.seed, key+"\x00"+strconv.Itoa(call)), uniqueLength)
+
+	suffix := "-" + n.runID + unique
+	maxLength := rt.MaxLength - len(rt.Prefix) - len(suffix)
+	clean := sanitize(rt, strings.TrimPrefix(base, rt.Prefix))
+
+	// Types such as CodeArtifact domains must start with a letter: retry with a leading "t" when the
+	// sanitized base does not satisfy the pattern on its own.
+	name := rt.Prefix + truncate(clean, maxLength) + suffix
+	if !rt.Pattern.MatchString(name) {
+		name = rt.Prefix + truncate("t"+clean, maxLength) + suffix
+	}
+
+	if !rt.Pattern.MatchString(name) {
+		return "", fmt.Errorf("generated %s name %q from base %q does not match %s", rt.Name, name, base, rt.Pattern)
+	}
+
+	return name, nil
+}
+
+// sanitize adapts base to the character rules of rt, replacing invalid runs with a hyphen. An empty
+// base becomes "t".
+func sanitize(rt ResourceType, base string) string {
+	if rt.Lowercase {
+		base = strings.ToLower(base)
+	}
+
+	base = strings.Trim(rt.Invalid.ReplaceAllString(base, "-"), "-")
+	if base == "" {
+		return "t"
+	}
+
+	return base
+}
+
+// truncate cuts base to maxLength characters, dropping trailing separators so the suffix hyphen
+// does not follow another separator.
+func truncate(base string, maxLength int) string {
+	if len(base) > maxLength {
+		base = base[:maxLength]
+	}
+
+	return strings.TrimRight(base, "-._/")
+}
+
+// digest hashes the seed and a key into a 64-bit value.
+func digest(seed int64, key string) uint64 {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(seed))
+
+	sum := sha256.Sum256(append(buf[:], key...))
+
+	return binary.BigEndian.Uint64(sum[:8])
+}
+
+// encode renders value in base 36, left-padded or truncated to length characters.
+func encode(value uint64, length int) string {
+	encoded := strconv.FormatUint(value, len(alphabet))
+	if len(encoded) < length {
+		encoded = strings.Repeat("0", length-len(encoded)) + encoded
+	}
+
+	return encoded[len(encoded)-length:]
+}
+
+// seedFromEnv reads the seed from TF_TEST_NAME_SEED, or draws a random one.
+func seedFromEnv() (int64, error) {
+	if value := os.Getenv(SeedEnvVar); value != "" {
+		return strconv.ParseInt(value, 10, 64)
+	}
+
+	n, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		return 0, fmt.Errorf("failed to draw a random seed: %w", err)
+	}
+
+	return n.Int64(), nil
+}
@@ -0,0 +1,94 @@
+package naming
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testSeed fixes the names of the tests below.
+const testSeed = 42
+
+func TestNameForWhenBaseVaries(t *testing.T) {
+	t.Parallel()
+
+	types := []ResourceType{Generic, S3Bucket, CodeArtifactDomain, CodeArtifactRepository, IAMRole, KMSAlias, LogGroup}
+
+	bases := map[string]string{
+		"simple":        "example-domain",
+		"invalid chars": "My_Domain.Name!! with spaces",
+		"leading digit": "1st-domain",
+		"empty":         "",
+		"prefixed":      "alias/example",
+		"long":          strings.Repeat("long-name-", 60),
+	}
+
+	for _, rt := range types {
+		for baseName, base := range bases {
+			t.Run(rt.Name+"/"+baseName, func(t *testing.T) {
+				t.Parallel()
+
+				n := New(testSeed)
+
+				name, err := n.NameFor(t.Name(), rt, base)
+				require.NoError(t, err, "Failed to generate a %s name", rt.Name)
+
+				require.LessOrEqual(t, len(name), rt.MaxLength, "Name %q exceeds the %s limit", name, rt.Name)
+				require.Regexp(t, rt.Pattern, name, "Name %q should match the %s rules", name, rt.Name)
+				require.True(t, strings.HasPrefix(name, rt.Prefix), "Name %q should start with %q", name, rt.Prefix)
+				require.NotRegexp(t, rt.Invalid, strings.TrimPrefix(name, rt.Prefix), "Name %q has invalid characters", name)
+
+				if rt.Lowercase {
+					require.Equal(t, strings.ToLower(name), name, "Name %q should be lower-case", name)
+				}
+
+				// pkg/golden replaces the suffix of the run with this pattern
+				suffix := regexp.MustCompile(`-` + regexp.QuoteMeta(n.RunID()) + `[0-9a-z]{6}$`)
+				require.Regexp(t, suffix, name, "Name %q should end with the run ID and a unique component", name)
+			})
+		}
+	}
+}
+
+func TestNameForWhenCalledTwiceInOneRun(t *testing.T) {
+	t.Parallel()
+
+	n := New(testSeed)
+
+	first, err := n.NameFor("TestOwner", CodeArtifactDomain, "example-domain")
+	require.NoError(t, err)
+
+	second, err := n.NameFor("TestOwner", CodeArtifactDomain, "example-domain")
+	require.NoError(t, err)
+
+	other, err := n.NameFor("TestOther", CodeArtifactDomain, "example-domain")
+	require.NoError(t, err)
+
+	require.NotEqual(t, first, second, "Two calls for the same test and base should get distinct names")
+	require.NotEqual(t, first, other, "Two tests with the same base should get distinct names")
+	require.NotEqual(t, second, other, "Two tests with the same base should get distinct names")
+}
+
+func TestNameForWhenSeedIsReused(t *testing.T) {
+	t.Parallel()
+
+	generate := func(seed int64) []string {
+		n := New(seed)
+
+		names := make([]string, 0, 3)
+		for i := 0; i < 3; i++ {
+			name, err := n.NameFor("TestOwner", S3Bucket, "bucket")
+			require.NoError(t, err)
+
+			names = append(names, name)
+		}
+
+		return names
+	}
+
+	require.Equal(t, generate(testSeed), generate(testSeed), "The same seed should reproduce the same names")
+	require.NotEqual(t, generate(testSeed), generate(testSeed+1), "Another seed should produce other names")
+	require.Len(t, New(testSeed).RunID(), runIDLength, "The run ID should have a fixed length")
+}
@@ -0,0 +1,239 @@
+// Package oidc runs a local OpenID Connect issuer over HTTPS, so that configurations reading the
+// certificate chain of an issuer can be planned without reaching a real identity provider.
+package oidc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // IAM OIDC thumbprints are SHA-1 fingerprints
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// DiscoveryPath is the path of the OpenID Connect discovery document, relative to the issuer URL.
+	DiscoveryPath = "/.well-known/openid-configuration"
+
+	// JWKSPath is the path of the issuer's JSON Web Key Set, relative to the issuer URL.
+	JWKSPath = "/.well-known/jwks.json"
+
+	// ProviderURLVariable is the foundation input variable the issuer URL is passed through.
+	ProviderURLVariable = "oidc_provider_url"
+
+	// certDirEnvVar lists the directories Go programs on Unix, the tls provider among them, read
+	// extra trusted certificates from.
+	certDirEnvVar = "SSL_CERT_DIR"
+)
+
+// defaultCertDirs are the directories Go reads trusted certificates from when SSL_CERT_DIR is not set.
+var defaultCertDirs = []string{"/etc/ssl/certs", "/etc/pki/tls/certs"}
+
+// Issuer is a local OpenID Connect issuer. Its server certificate is signed by a CA generated for
+// the test, and the server presents both, so the CA is the top certificate of the chain.
+type Issuer struct {
+	// URL is the issuer URL, "https://127.0.0.1:<port>", without a trailing slash.
+	URL string
+
+	// CA is the certificate authority that signs the server certificate.
+	CA *x509.Certificate
+
+	// CertDir is the directory holding the PEM-encoded CA, trusted by Terraform through SSL_CERT_DIR.
+	CertDir string
+}
+
+// NewIssuer starts an issuer serving its discovery document and an empty key set, stopped through
+// t.Cleanup. Terraform trusts the generated CA through SSL_CERT_DIR, which Go ignores on macOS and
+// Windows, so the test is skipped there.
+func NewIssuer(t *testing.T) *Issuer {
+	t.Helper()
+
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		t.Skipf("⏭️ Providers on %s do not read trusted certificates from %s", runtime.GOOS, certDirEnvVar)
+	}
+
+	ca, caKey, err := newCA()
+	require.NoError(t, err, "Failed to generate the issuer CA")
+
+	leaf, leafKey, err := newServerCertificate(ca, caKey)
+	require.NoError(t, err, "Failed to generate the issuer server certificate")
+
+	issuer := &Issuer{CA: ca, CertDir: t.TempDir()}
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw})
+	require.NoError(t, os.WriteFile(filepath.Join(issuer.CertDir, "oidc-issuer-ca.pem"), caPEM, 0o600),
+		"Failed to write the issuer CA")
+
+	server := httptest.NewUnstartedServer(issuer.handler())
+	server.TLS = &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		Certificates: []tls.Certificate{{
+			Certificate: [][]byte{leaf.Raw, ca.Raw},
+			PrivateKey:  leafKey,
+			Leaf:        leaf,
+		}},
+	}
+
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	issuer.URL = server.URL
+
+	t.Logf("🔐 Local OIDC issuer listening at: %s (CA thumbprint %s)", issuer.URL, issuer.Thumbprint())
+
+	return issuer
+}
+
+// Thumbprint returns the SHA-1 fingerprint of the CA, in lowercase hex: the sha1_fingerprint the
+// tls_certificate data source reports for the first certificate, the root, of the issuer's chain.
+func (i *Issuer) Thumbprint() string {
+	sum := sha1.Sum(i.CA.Raw) //nolint:gosec // IAM OIDC thumbprints are SHA-1 fingerprints
+
+	return hex.EncodeToString(sum[:])
+}
+
+// TerraformOption passes the issuer URL as the oidc_provider_url variable and makes Terraform trust
+// the generated CA, in addition to the system certificates.
+func (i *Issuer) TerraformOption() helper.SetupOption {
+	dirs := defaultCertDirs
+	if configured := os.Getenv(certDirEnvVar); configured != "" {
+		dirs = filepath.SplitList(configured)
+	}
+
+	return helper.WithOptions(
+		helper.WithVars(map[string]interface{}{ProviderURLVariable: i.URL}),
+		helper.WithEnv(map[string]string{
+			certDirEnvVar: strings.Join(append([]string{i.CertDir}, dirs...), string(os.PathListSeparator)),
+		}),
+	)
+}
+
+// handler serves the discovery document, the key set, and an empty page at the issuer URL itself,
+// which the tls_certificate data source requests to read the chain.
+func (i *Issuer) handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc(DiscoveryPath, func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"issuer":                                i.URL,
+			"jwks_uri":                              i.URL + JWKSPath,
+			"response_types_supported":              []string{"id_token"},
+			"subject_types_supported":               []string{"public"},
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+			"claims_supported":                      []string{"sub", "aud", "iss"},
+		})
+	})
+
+	mux.HandleFunc(JWKSPath, func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, map[string]interface{}{"keys": []interface{}{}})
+	})
+
+	mux.HandleFunc("/{$}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	return mux
+}
+
+// writeJSON writes value as a JSON response.
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// newCA generates a self-signed certificate authority valid for the duration of a test run.
+func newCA() (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template, err := newTemplate("terratest OIDC issuer CA")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+
+	cert, err := createCertificate(template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cert, key, nil
+}
+
+// newServerCertificate generates a certificate for 127.0.0.1 and localhost signed by the CA.
+func newServerCertificate(ca *x509.Certificate, caKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template, err := newTemplate("localhost")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template.DNSNames = []string{"localhost"}
+	template.IPAddresses = []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback}
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+
+	cert, err := createCertificate(template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cert, key, nil
+}
+
+// newTemplate returns a certificate template with a random serial number, valid for a day.
+func newTemplate(commonName string) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate a serial number: %w", err)
+	}
+
+	now := time.Now()
+
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(24 * time.Hour),
+	}, nil
+}
+
+// createCertificate signs the template with the parent's key and parses the result.
+func createCertificate(template, parent *x509.Certificate, publicKey *ecdsa.PublicKey, signer *ecdsa.PrivateKey) (*x509.Certificate, error) {
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, publicKey, signer)
+	if err != nil {
+		return nil, err
+	}
+
+	return x509.ParseCertificate(der)
+}
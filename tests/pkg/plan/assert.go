@@ -0,0 +1,80 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// RequireResourcePlanned fails the test unless the plan contains a change for the given address.
+func RequireResourcePlanned(t *testing.T, p *Plan, address string) *ResourceChange {
+	t.Helper()
+
+	rc, ok := p.ResourceChange(address)
+	require.Truef(t, ok, "Plan should contain resource %q; planned resources: %v", address, p.Addresses())
+
+	return rc
+}
+
+// RequireResourceNotPlanned fails the test if the plan contains a change for the given address.
+func RequireResourceNotPlanned(t *testing.T, p *Plan, address string) {
+	t.Helper()
+
+	require.Falsef(t, p.HasResource(address), "Plan should not contain resource %q", address)
+}
+
+// RequireResourceAction fails the test unless the resource at the given address is planned with
+// exactly the given actions (e.g. ActionCreate, or ActionDelete and ActionCreate for a replacement).
+func RequireResourceAction(t *testing.T, p *Plan, address string, actions ...Action) {
+	t.Helper()
+
+	rc := RequireResourcePlanned(t, p, address)
+	require.ElementsMatchf(t, actions, rc.Change.Actions,
+		"Resource %q should be planned with actions %v, got %q", address, actions, rc.Change.ActionString())
+}
+
+// RequireResourceTypePlanned fails the test unless at least one managed resource of the given type is planned.
+func RequireResourceTypePlanned(t *testing.T, p *Plan, resourceType string) {
+	t.Helper()
+
+	require.NotEmptyf(t, p.ResourceChangesByType(resourceType),
+		"Plan should contain at least one %s resource; planned resources: %v", resourceType, p.Addresses())
+}
+
+// RequireResourceTypeNotPlanned fails the test if any managed resource of the given type is planned.
+func RequireResourceTypeNotPlanned(t *testing.T, p *Plan, resourceType string) {
+	t.Helper()
+
+	var addresses []string
+	for _, rc := range p.ResourceChangesByType(resourceType) {
+		addresses = append(addresses, rc.Address)
+	}
+
+	require.Emptyf(t, addresses, "Plan should not contain any %s resource", resourceType)
+}
+
+// RequireAfterAttribute fails the test unless the resource at the given address has the expected
+// planned value at the attribute path. See Change.AfterAttribute for the path syntax.
+func RequireAfterAttribute(t *testing.T, p *Plan, address, path string, expected interface{}) {
+	t.Helper()
+
+	rc := RequireResourcePlanned(t, p, address)
+
+	value, ok := rc.Change.AfterAttribute(path)
+	require.Truef(t, ok, "Resource %q should have a known planned value at %q", address, path)
+	require.EqualValuesf(t, expected, value, "Unexpected planned value for %q at %q", address, path)
+}
+
+// RequireNoManagedResources fails the test if the plan creates, updates or deletes any managed resource.
+func RequireNoManagedResources(t *testing.T, p *Plan) {
+	t.Helper()
+
+	var addresses []string
+	for _, rc := range p.ManagedResourceChanges() {
+		if !rc.Change.IsNoOp() {
+			addresses = append(addresses, rc.Address)
+		}
+	}
+
+	require.Emptyf(t, addresses, "Plan should not change any managed resource")
+}
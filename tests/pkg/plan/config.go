@@ -0,0 +1,54 @@
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// configuration is the subset of the configuration representation of a plan used to follow the
+// references between module calls.
+type configuration struct {
+	RootModule struct {
+		ModuleCalls map[string]struct {
+			Source      string `json:"source"`
+			Expressions map[string]struct {
+				References []string `json:"references"`
+			} `json:"expressions"`
+		} `json:"module_calls"`
+	} `json:"root_module"`
+}
+
+// ModuleCallReferences returns the references of the expression the root module passes to an input
+// of a module call, e.g. ["module.foundation.kms_key_arn", "module.foundation"] for
+// `kms_key_arn = module.foundation.kms_key_arn`. Constant expressions have no references.
+func (p *Plan) ModuleCallReferences(call, input string) ([]string, error) {
+	var config configuration
+	if err := json.Unmarshal(p.Configuration, &config); err != nil {
+		return nil, fmt.Errorf("failed to decode plan configuration: %w", err)
+	}
+
+	moduleCall, ok := config.RootModule.ModuleCalls[call]
+	if !ok {
+		return nil, fmt.Errorf("root module does not call module %q", call)
+	}
+
+	expression, ok := moduleCall.Expressions[input]
+	if !ok {
+		return nil, fmt.Errorf("module %q is not passed input %q", call, input)
+	}
+
+	return expression.References, nil
+}
+
+// RequireModuleInputReference fails the test unless the root module passes an input of a module
+// call an expression that references reference, e.g. "module.foundation.kms_key_arn".
+func RequireModuleInputReference(t *testing.T, p *Plan, call, input, reference string) {
+	t.Helper()
+
+	references, err := p.ModuleCallReferences(call, input)
+	require.NoError(t, err, "Failed to read the references of module.%s input %q", call, input)
+	require.Containsf(t, references, reference, "Input %q of module.%s should be wired to %s", input, call, reference)
+}
@@ -0,0 +1,218 @@
+package plan
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+// Exit codes of `terraform plan -detailed-exitcode`.
+const (
+	ExitCodeNoChanges = 0
+	ExitCodeChanges   = 2
+)
+
+// Difference is a resource or output that a plan still changes, with the attribute paths that differ.
+type Difference struct {
+	Address    string
+	Actions    string
+	Attributes []string
+}
+
+// String formats the difference as "<address> (<actions>): <attribute>, ...".
+func (d Difference) String() string {
+	if len(d.Attributes) == 0 {
+		return fmt.Sprintf("%s (%s)", d.Address, d.Actions)
+	}
+
+	return fmt.Sprintf("%s (%s): %s", d.Address, d.Actions, strings.Join(d.Attributes, ", "))
+}
+
+// PlanExitCodeE plans an applied configuration with `-detailed-exitcode` and returns the exit code.
+// When the plan has changes, it is also returned parsed. The caller's options are not modified.
+func PlanExitCodeE(t *testing.T, options *terraform.Options) (int, *Plan, error) {
+	planOptions, err := options.Clone()
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to clone Terraform options: %w", err)
+	}
+
+	planOptions.PlanFilePath = filepath.Join(t.TempDir(), planFileName)
+
+	exitCode, err := terraform.PlanExitCodeE(t, planOptions)
+	if err != nil {
+		return exitCode, nil, fmt.Errorf("terraform plan failed: %w", err)
+	}
+
+	switch exitCode {
+	case ExitCodeNoChanges:
+		return exitCode, nil, nil
+	case ExitCodeChanges:
+		p, err := ShowE(t, planOptions)
+		return exitCode, p, err
+	default:
+		return exitCode, nil, fmt.Errorf("terraform plan failed with exit code %d", exitCode)
+	}
+}
+
+// RequireIdempotent plans an applied configuration again with `-detailed-exitcode` and fails the
+// test if the plan is not empty, listing the resources and outputs that still change and their
+// differing attributes. Perpetual diffs usually come from policy JSON and tags.
+func RequireIdempotent(t *testing.T, options *terraform.Options) {
+	t.Helper()
+
+	exitCode, p, err := PlanExitCodeE(t, options)
+	require.NoError(t, err, "Failed to plan the applied configuration")
+
+	if exitCode == ExitCodeNoChanges {
+		t.Log("✅ Second plan is empty: the configuration is idempotent")
+		return
+	}
+
+	var lines []string
+	for _, difference := range p.Differences() {
+		lines = append(lines, "  - "+difference.String())
+	}
+
+	require.Failf(t, "Configuration is not idempotent",
+		"A plan after apply should be empty, but it still changes:\n%s", strings.Join(lines, "\n"))
+}
+
+// Differences returns the resources and outputs the plan changes, sorted by address, with the
+// attribute paths whose planned value differs from the prior one or is unknown.
+func (p *Plan) Differences() []Difference {
+	var differences []Difference
+
+	for _, rc := range p.ManagedResourceChanges() {
+		if rc.Change.IsNoOp() {
+			continue
+		}
+
+		differences = append(differences, Difference{
+			Address:    rc.Address,
+			Actions:    rc.Change.ActionString(),
+			Attributes: rc.Change.ChangedAttributes(),
+		})
+	}
+
+	for _, name := range sortedKeys(p.OutputChanges) {
+		change := p.OutputChanges[name]
+		if change.IsNoOp() {
+			continue
+		}
+
+		differences = append(differences, Difference{
+			Address: "output." + name,
+			Actions: change.ActionString(),
+		})
+	}
+
+	sort.SliceStable(differences, func(i, j int) bool {
+		return differences[i].Address < differences[j].Address
+	})
+
+	return differences
+}
+
+// ChangedAttributes returns the sorted paths, in the Change.AfterAttribute syntax, of the
+// attributes whose planned value differs from the prior one or is unknown until apply.
+func (c *Change) ChangedAttributes() []string {
+	paths := map[string]bool{}
+
+	diffPaths("", c.Before, c.After, paths)
+	unknownPaths("", c.AfterUnknown, paths)
+
+	return sortedKeys(paths)
+}
+
+// diffPaths records the paths under prefix where before and after differ. Objects are compared
+// key by key; lists of equal length element by element; anything else as a whole.
+func diffPaths(prefix string, before, after interface{}, paths map[string]bool) {
+	beforeMap, beforeIsMap := before.(map[string]interface{})
+	afterMap, afterIsMap := after.(map[string]interface{})
+
+	if beforeIsMap && afterIsMap {
+		keys := map[string]bool{}
+		for key := range beforeMap {
+			keys[key] = true
+		}
+
+		for key := range afterMap {
+			keys[key] = true
+		}
+
+		for key := range keys {
+			diffPaths(joinPath(prefix, key), beforeMap[key], afterMap[key], paths)
+		}
+
+		return
+	}
+
+	beforeList, beforeIsList := before.([]interface{})
+	afterList, afterIsList := after.([]interface{})
+
+	if beforeIsList && afterIsList && len(beforeList) == len(afterList) {
+		for i := range beforeList {
+			diffPaths(joinPath(prefix, fmt.Sprint(i)), beforeList[i], afterList[i], paths)
+		}
+
+		return
+	}
+
+	if !reflect.DeepEqual(before, after) {
+		paths[pathOrRoot(prefix)] = true
+	}
+}
+
+// unknownPaths records the paths under prefix that after_unknown marks as unknown.
+func unknownPaths(prefix string, unknown interface{}, paths map[string]bool) {
+	switch typed := unknown.(type) {
+	case bool:
+		if typed {
+			paths[pathOrRoot(prefix)] = true
+		}
+	case map[string]interface{}:
+		for key, value := range typed {
+			unknownPaths(joinPath(prefix, key), value, paths)
+		}
+	case []interface{}:
+		for i, value := range typed {
+			unknownPaths(joinPath(prefix, fmt.Sprint(i)), value, paths)
+		}
+	}
+}
+
+// joinPath appends a segment to a dotted attribute path.
+func joinPath(prefix, segment string) string {
+	if prefix == "" {
+		return segment
+	}
+
+	return prefix + "." + segment
+}
+
+// pathOrRoot names the whole value when the path is empty.
+func pathOrRoot(path string) string {
+	if path == "" {
+		return "(value)"
+	}
+
+	return path
+}
+
+// sortedKeys returns the keys of a map in order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,357 @@
+// Package plan provides typed access to the JSON representation of a saved Terraform plan.
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Action represents a single planned action on a resource or output.
+type Action string
+
+// Actions reported by `terraform show -json` in the change representation.
+const (
+	ActionNoOp   Action = "no-op"
+	ActionCreate Action = "create"
+	ActionRead   Action = "read"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// Resource modes reported by `terraform show -json`.
+const (
+	ModeManaged = "managed"
+	ModeData    = "data"
+)
+
+// Plan is the subset of the Terraform plan JSON representation used by the test suites.
+type Plan struct {
+	FormatVersion    string                     `json:"format_version"`
+	TerraformVersion string                     `json:"terraform_version"`
+	Variables        map[string]Variable        `json:"variables"`
+	PlannedValues    Values                     `json:"planned_values"`
+	ResourceChanges  []ResourceChange           `json:"resource_changes"`
+	ResourceDrift    []ResourceChange           `json:"resource_drift"`
+	OutputChanges    map[string]Change          `json:"output_changes"`
+	PriorState       *State                     `json:"prior_state,omitempty"`
+	Configuration    json.RawMessage            `json:"configuration"`
+	Errored          bool                       `json:"errored"`
+	changesByAddress map[string]*ResourceChange // Index over ResourceChanges, built by Parse.
+}
+
+// Variable is an input variable value as recorded in the plan.
+type Variable struct {
+	Value interface{} `json:"value"`
+}
+
+// State is a state snapshot embedded in the plan (prior state).
+type State struct {
+	FormatVersion    string `json:"format_version"`
+	TerraformVersion string `json:"terraform_version"`
+	Values           Values `json:"values"`
+}
+
+// Values holds the planned or prior values of outputs and the root module.
+type Values struct {
+	Outputs    map[string]OutputValue `json:"outputs"`
+	RootModule Module                 `json:"root_module"`
+}
+
+// OutputValue is the planned value of a root module output.
+type OutputValue struct {
+	Sensitive bool        `json:"sensitive"`
+	Value     interface{} `json:"value"`
+}
+
+// Module is a module instance in the planned values tree.
+type Module struct {
+	Address      string     `json:"address"`
+	Resources    []Resource `json:"resources"`
+	ChildModules []Module   `json:"child_modules"`
+}
+
+// Resource is a resource instance in the planned values tree.
+type Resource struct {
+	Address         string                 `json:"address"`
+	Mode            string                 `json:"mode"`
+	Type            string                 `json:"type"`
+	Name            string                 `json:"name"`
+	Index           interface{}            `json:"index,omitempty"`
+	ProviderName    string                 `json:"provider_name"`
+	SchemaVersion   int                    `json:"schema_version"`
+	Values          map[string]interface{} `json:"values"`
+	SensitiveValues interface{}            `json:"sensitive_values"`
+	DependsOn       []string               `json:"depends_on,omitempty"` // Configuration addresses of the dependencies, in state only.
+}
+
+// ResourceChange describes the planned change for a single resource instance.
+type ResourceChange struct {
+	Address       string      `json:"address"`
+	ModuleAddress string      `json:"module_address,omitempty"`
+	Mode          string      `json:"mode"`
+	Type          string      `json:"type"`
+	Name          string      `json:"name"`
+	Index         interface{} `json:"index,omitempty"`
+	ProviderName  string      `json:"provider_name"`
+	Change        Change      `json:"change"`
+	ActionReason  string      `json:"action_reason,omitempty"`
+}
+
+// Change holds the before/after representation of a resource or output change.
+type Change struct {
+	Actions         []Action    `json:"actions"`
+	Before          interface{} `json:"before"`
+	After           interface{} `json:"after"`
+	AfterUnknown    interface{} `json:"after_unknown"`
+	BeforeSensitive interface{} `json:"before_sensitive"`
+	AfterSensitive  interface{} `json:"after_sensitive"`
+	ReplacePaths    interface{} `json:"replace_paths,omitempty"`
+}
+
+// Parse decodes the output of `terraform show -json <planfile>` into a Plan.
+func Parse(data []byte) (*Plan, error) {
+	var p Plan
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to decode plan JSON: %w", err)
+	}
+
+	if p.FormatVersion == "" {
+		return nil, fmt.Errorf("plan JSON has no format_version; is this the output of 'terraform show -json <planfile>'?")
+	}
+
+	p.index()
+
+	return &p, nil
+}
+
+// index builds the lookup tables used by the query methods.
+func (p *Plan) index() {
+	p.changesByAddress = make(map[string]*ResourceChange, len(p.ResourceChanges))
+	for i := range p.ResourceChanges {
+		rc := &p.ResourceChanges[i]
+		p.changesByAddress[rc.Address] = rc
+	}
+}
+
+// ResourceChange returns the change planned for the resource at the given full address
+// (e.g. "module.this.aws_kms_key.this[0]").
+func (p *Plan) ResourceChange(address string) (*ResourceChange, bool) {
+	rc, ok := p.changesByAddress[address]
+	return rc, ok
+}
+
+// HasResource reports whether the plan contains a change of any kind for the given full address.
+func (p *Plan) HasResource(address string) bool {
+	_, ok := p.changesByAddress[address]
+	return ok
+}
+
+// Addresses returns the sorted addresses of every resource change in the plan.
+func (p *Plan) Addresses() []string {
+	addresses := make([]string, 0, len(p.ResourceChanges))
+	for i := range p.ResourceChanges {
+		addresses = append(addresses, p.ResourceChanges[i].Address)
+	}
+
+	sort.Strings(addresses)
+
+	return addresses
+}
+
+// ManagedResourceChanges returns the changes for managed resources only, ignoring data sources.
+func (p *Plan) ManagedResourceChanges() []*ResourceChange {
+	return p.filter(func(rc *ResourceChange) bool {
+		return rc.Mode == ModeManaged
+	})
+}
+
+// ResourceChangesByType returns the changes for every managed resource of the given type
+// (e.g. "aws_s3_bucket"), regardless of module.
+func (p *Plan) ResourceChangesByType(resourceType string) []*ResourceChange {
+	return p.filter(func(rc *ResourceChange) bool {
+		return rc.Mode == ModeManaged && rc.Type == resourceType
+	})
+}
+
+// ResourceChangesInModule returns the changes for every resource whose module address is
+// moduleAddress or nested below it (e.g. "module.this[0]").
+func (p *Plan) ResourceChangesInModule(moduleAddress string) []*ResourceChange {
+	return p.filter(func(rc *ResourceChange) bool {
+		return rc.ModuleAddress == moduleAddress || strings.HasPrefix(rc.ModuleAddress, moduleAddress+".")
+	})
+}
+
+// ResourceChangesWithAction returns the changes for managed resources planned with the given action.
+// Replacements match both ActionCreate and ActionDelete.
+func (p *Plan) ResourceChangesWithAction(action Action) []*ResourceChange {
+	return p.filter(func(rc *ResourceChange) bool {
+		return rc.Mode == ModeManaged && rc.Change.HasAction(action)
+	})
+}
+
+// Output returns the planned value of a root module output.
+func (p *Plan) Output(name string) (OutputValue, bool) {
+	v, ok := p.PlannedValues.Outputs[name]
+	return v, ok
+}
+
+// OutputChange returns the planned change of a root module output.
+func (p *Plan) OutputChange(name string) (*Change, bool) {
+	c, ok := p.OutputChanges[name]
+	if !ok {
+		return nil, false
+	}
+
+	return &c, true
+}
+
+// filter returns the resource changes matching the predicate, in plan order.
+func (p *Plan) filter(match func(*ResourceChange) bool) []*ResourceChange {
+	var matches []*ResourceChange
+	for i := range p.ResourceChanges {
+		if match(&p.ResourceChanges[i]) {
+			matches = append(matches, &p.ResourceChanges[i])
+		}
+	}
+
+	return matches
+}
+
+// HasAction reports whether the change includes the given action.
+func (c *Change) HasAction(action Action) bool {
+	for _, a := range c.Actions {
+		if a == action {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsNoOp reports whether the change leaves the object untouched.
+func (c *Change) IsNoOp() bool {
+	return len(c.Actions) == 1 && c.Actions[0] == ActionNoOp
+}
+
+// IsCreate reports whether the change only creates the object.
+func (c *Change) IsCreate() bool {
+	return len(c.Actions) == 1 && c.Actions[0] == ActionCreate
+}
+
+// IsRead reports whether the change reads a data source during apply.
+func (c *Change) IsRead() bool {
+	return len(c.Actions) == 1 && c.Actions[0] == ActionRead
+}
+
+// IsUpdate reports whether the change updates the object in place.
+func (c *Change) IsUpdate() bool {
+	return len(c.Actions) == 1 && c.Actions[0] == ActionUpdate
+}
+
+// IsDelete reports whether the change only deletes the object.
+func (c *Change) IsDelete() bool {
+	return len(c.Actions) == 1 && c.Actions[0] == ActionDelete
+}
+
+// IsReplace reports whether the change replaces the object, in either
+// delete-then-create or create-then-delete order.
+func (c *Change) IsReplace() bool {
+	return len(c.Actions) == 2 && c.HasAction(ActionCreate) && c.HasAction(ActionDelete)
+}
+
+// ActionString returns a human readable summary of the planned actions (e.g. "delete,create").
+func (c *Change) ActionString() string {
+	parts := make([]string, len(c.Actions))
+	for i, a := range c.Actions {
+		parts[i] = string(a)
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// AfterAttribute returns the planned value at the given attribute path. Path segments are
+// separated by dots and list elements are addressed by their index (e.g. "tags.Name" or
+// "rule.0.apply_server_side_encryption_by_default.0.sse_algorithm").
+// The second return value is false when the path does not exist or the value is unknown
+// until apply.
+func (c *Change) AfterAttribute(path string) (interface{}, bool) {
+	if c.IsAfterUnknown(path) {
+		return nil, false
+	}
+
+	return Lookup(c.After, path)
+}
+
+// BeforeAttribute returns the prior value at the given attribute path.
+func (c *Change) BeforeAttribute(path string) (interface{}, bool) {
+	return Lookup(c.Before, path)
+}
+
+// IsAfterUnknown reports whether the value at the given attribute path (or one of its parents)
+// is only known after apply.
+func (c *Change) IsAfterUnknown(path string) bool {
+	if b, ok := c.AfterUnknown.(bool); ok {
+		return b
+	}
+
+	current := c.AfterUnknown
+	for _, segment := range splitPath(path) {
+		next, ok := step(current, segment)
+		if !ok {
+			return false
+		}
+
+		if b, ok := next.(bool); ok {
+			return b
+		}
+
+		current = next
+	}
+
+	return false
+}
+
+// Lookup walks a decoded JSON value following a dot separated attribute path.
+func Lookup(value interface{}, path string) (interface{}, bool) {
+	current := value
+	for _, segment := range splitPath(path) {
+		next, ok := step(current, segment)
+		if !ok {
+			return nil, false
+		}
+
+		current = next
+	}
+
+	return current, true
+}
+
+// splitPath splits an attribute path into its segments. An empty path addresses the root value.
+func splitPath(path string) []string {
+	if path == "" {
+		return nil
+	}
+
+	return strings.Split(path, ".")
+}
+
+// step descends one segment into a decoded JSON object or list.
+func step(value interface{}, segment string) (interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		next, ok := v[segment]
+		return next, ok
+	case []interface{}:
+		i, err := strconv.Atoi(segment)
+		if err != nil || i < 0 || i >= len(v) {
+			return nil, false
+		}
+
+		return v[i], true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,75 @@
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+)
+
+// ParseState decodes the JSON produced by `terraform show -json` for the current state.
+// An empty state, as left behind by `terraform destroy`, has no values and parses to no resources.
+func ParseState(data []byte) (*State, error) {
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to decode state JSON: %w", err)
+	}
+
+	if s.FormatVersion == "" {
+		return nil, fmt.Errorf("state JSON has no format_version; is it the output of `terraform show -json`?")
+	}
+
+	return &s, nil
+}
+
+// ShowStateE runs `terraform show -json` on the current state of options.TerraformDir and returns it parsed.
+// The caller's options are not modified; a saved plan referenced by options.PlanFilePath is ignored.
+func ShowStateE(t *testing.T, options *terraform.Options) (*State, error) {
+	stateOptions, err := options.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("failed to clone Terraform options: %w", err)
+	}
+
+	stateOptions.PlanFilePath = ""
+
+	out, err := terraform.ShowE(t, stateOptions)
+	if err != nil {
+		return nil, fmt.Errorf("terraform show failed for the state of %s: %w", options.TerraformDir, err)
+	}
+
+	return ParseState([]byte(out))
+}
+
+// Resources returns every resource instance of the state, in all modules, managed and data alike.
+func (s *State) Resources() []Resource {
+	return s.Values.RootModule.AllResources()
+}
+
+// ManagedResources returns every managed resource instance of the state, in all modules.
+func (s *State) ManagedResources() []Resource {
+	var managed []Resource
+	for _, r := range s.Resources() {
+		if r.Mode == ModeManaged {
+			managed = append(managed, r)
+		}
+	}
+
+	return managed
+}
+
+// AllResources returns the resources of the module and, recursively, of its child modules.
+func (m Module) AllResources() []Resource {
+	resources := append([]Resource{}, m.Resources...)
+	for _, child := range m.ChildModules {
+		resources = append(resources, child.AllResources()...)
+	}
+
+	return resources
+}
+
+// StringValue returns the string attribute of a resource, if present.
+func (r Resource) StringValue(name string) (string, bool) {
+	value, ok := r.Values[name].(string)
+	return value, ok && value != ""
+}
@@ -0,0 +1,75 @@
+package plan
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+// planFileName is the name of the saved plan written inside the test's temporary directory.
+const planFileName = "tfplan"
+
+// Show runs `terraform show -json` on the saved plan referenced by options.PlanFilePath
+// and returns the parsed plan. The test fails if the command or decoding fails.
+func Show(t *testing.T, options *terraform.Options) *Plan {
+	p, err := ShowE(t, options)
+	require.NoError(t, err, "Failed to show Terraform plan as JSON")
+
+	return p
+}
+
+// ShowE runs `terraform show -json` on the saved plan referenced by options.PlanFilePath
+// and returns the parsed plan.
+func ShowE(t *testing.T, options *terraform.Options) (*Plan, error) {
+	if options.PlanFilePath == "" {
+		return nil, fmt.Errorf("options.PlanFilePath must reference a saved plan")
+	}
+
+	out, err := terraform.ShowE(t, options)
+	if err != nil {
+		return nil, fmt.Errorf("terraform show failed for plan %s: %w", options.PlanFilePath, err)
+	}
+
+	return Parse([]byte(out))
+}
+
+// InitAndPlan runs `terraform init`, saves a plan to a temporary file and returns it parsed.
+// The test fails if any step fails.
+func InitAndPlan(t *testing.T, options *terraform.Options) *Plan {
+	p, err := InitAndPlanE(t, options)
+	require.NoError(t, err, "Failed to init and plan Terraform configuration")
+
+	return p
+}
+
+// InitAndPlanE runs `terraform init`, saves a plan to a temporary file and returns it parsed.
+// Init goes through helper.InitE so parallel tests can share the provider cache safely.
+func InitAndPlanE(t *testing.T, options *terraform.Options) (*Plan, error) {
+	if _, err := helper.InitE(t, options); err != nil {
+		return nil, fmt.Errorf("terraform init failed: %w", err)
+	}
+
+	return PlanE(t, options)
+}
+
+// PlanE saves a plan of an already initialized configuration to a temporary file and returns it
+// parsed. The caller's options are not modified, so a later `terraform apply` with the same
+// options still plans and applies from scratch.
+func PlanE(t *testing.T, options *terraform.Options) (*Plan, error) {
+	planOptions, err := options.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("failed to clone Terraform options: %w", err)
+	}
+
+	planOptions.PlanFilePath = filepath.Join(t.TempDir(), planFileName)
+
+	if _, err := terraform.PlanE(t, planOptions); err != nil {
+		return nil, fmt.Errorf("terraform plan failed: %w", err)
+	}
+
+	return ShowE(t, planOptions)
+}
@@ -0,0 +1,115 @@
+// Package recipe plans every discovered example and fixture combination of a module.
+package recipe
+
+import (
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/golden"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/plan"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/repo"
+	"github.com/stretchr/testify/require"
+)
+
+// noFixtureName is the subtest name used for examples that have no fixtures/ directory.
+const noFixtureName = "no-fixture"
+
+// AssertFunc runs fixture specific assertions against a successfully saved plan.
+type AssertFunc func(t *testing.T, example repo.Example, fixture repo.Fixture, p *plan.Plan)
+
+// Config controls how the recipes of a module are planned.
+type Config struct {
+	// Skip maps "<example path>/<fixture name>" (e.g. "foundation/basic/oidc-existing") to the reason the
+	// recipe cannot be planned in a clean account, such as a fixture that looks up pre-existing resources.
+	Skip map[string]string
+
+	// Upgrade runs `terraform init -upgrade` so local module sources are always reinstalled.
+	Upgrade bool
+
+	// Assert, when set, is called with the parsed plan of every recipe that was planned.
+	Assert AssertFunc
+
+	// Golden compares the normalized plan of every recipe with its golden file under
+	// tests/modules/<module>/golden/. Run the test with -update to rewrite the files.
+	Golden bool
+}
+
+// Run discovers every example of the given module and plans each of its fixtures in a parallel subtest
+// named "<example path>/<fixture name>". Examples without fixtures are planned once without var files.
+// The test fails if the module has no examples.
+func Run(t *testing.T, moduleName string, cfg Config) {
+	t.Helper()
+
+	dirs, err := repo.NewTFSourcesDir()
+	require.NoError(t, err, "Failed to get Terraform sources directory")
+
+	examples, err := dirs.ListExamples(moduleName)
+	require.NoError(t, err, "Failed to discover examples for module %s", moduleName)
+	require.NotEmpty(t, examples, "No examples found for module %s", moduleName)
+
+	RunExamples(t, examples, cfg)
+}
+
+// RunExamples plans each fixture of the given examples in a parallel subtest.
+func RunExamples(t *testing.T, examples []repo.Example, cfg Config) {
+	t.Helper()
+
+	for _, example := range examples {
+		example := example
+
+		t.Run(example.Path, func(t *testing.T) {
+			t.Parallel()
+
+			if len(example.Fixtures) == 0 {
+				t.Run(noFixtureName, func(t *testing.T) {
+					t.Parallel()
+					planRecipe(t, example, repo.Fixture{Name: noFixtureName}, cfg)
+				})
+
+				return
+			}
+
+			for _, fixture := range example.Fixtures {
+				fixture := fixture
+
+				t.Run(fixture.Name, func(t *testing.T) {
+					t.Parallel()
+					planRecipe(t, example, fixture, cfg)
+				})
+			}
+		})
+	}
+}
+
+// planRecipe initializes and plans a single example/fixture combination and runs the configured assertions.
+func planRecipe(t *testing.T, example repo.Example, fixture repo.Fixture, cfg Config) {
+	if reason, ok := cfg.Skip[example.Path+"/"+fixture.Name]; ok {
+		t.Skipf("⏭️ Skipping %s/%s: %s", example.Path, fixture.Name, reason)
+	}
+
+	// Fixtures of the same example run in parallel, so each one plans in its own copy of the example
+	opts := []helper.SetupOption{helper.WithWorkspaceCopy()}
+	if cfg.Upgrade {
+		opts = append(opts, helper.WithUpgrade())
+	}
+	if fixture.File != "" {
+		opts = append(opts, helper.WithVarFiles(fixture.VarFile()))
+	}
+
+	terraformOptions := helper.NewTerraformOptions(t, helper.ExampleSource(example.Path), opts...)
+
+	t.Logf("🔍 Terraform Example Directory: %s", terraformOptions.TerraformDir)
+	t.Logf("📝 Using fixture: %s", fixture.Name)
+
+	tfPlan, err := plan.InitAndPlanE(t, terraformOptions)
+	require.NoError(t, err, "Terraform init and plan failed for %s/%s", example.Path, fixture.Name)
+	t.Logf("📝 Planned resources: %v", tfPlan.Addresses())
+
+	if cfg.Assert != nil {
+		cfg.Assert(t, example, fixture, tfPlan)
+	}
+
+	if cfg.Golden {
+		golden.RequirePlan(t, example, fixture.Name, tfPlan)
+	}
+}
@@ -0,0 +1,277 @@
+package repo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Constants used to discover example fixtures.
+const (
+	fixturesDir      = "fixtures"
+	fixtureExtension = ".tfvars"
+)
+
+// localModuleSourcePattern matches a module block source pointing at a module of this repository,
+// e.g. source = "../../../modules/domain".
+var localModuleSourcePattern = regexp.MustCompile(`source\s*=\s*"(?:\.\./)+modules/([^/"]+)/?"`)
+
+// Example represents an example configuration that exercises a module.
+type Example struct {
+	Module   string    // The name of the module the example belongs to, e.g. "domain".
+	Name     string    // The name of the example, e.g. "basic".
+	Path     string    // The path relative to the examples directory, e.g. "domain/basic".
+	Dir      string    // The absolute path to the example directory.
+	Fixtures []Fixture // The fixtures found in the example's fixtures/ directory.
+}
+
+// Fixture represents a .tfvars file in an example's fixtures/ directory.
+type Fixture struct {
+	Name string // The fixture name without extension, e.g. "default".
+	File string // The fixture file name, e.g. "default.tfvars".
+	Path string // The absolute path to the fixture file.
+}
+
+// VarFile returns the fixture path relative to its example directory, as expected in terraform.Options.VarFiles.
+func (f Fixture) VarFile() string {
+	return filepath.Join(fixturesDir, f.File)
+}
+
+// ID returns a stable identifier for the example, usable as a subtest name.
+func (e Example) ID() string {
+	return e.Path
+}
+
+// Fixture returns the example fixture with the given name (with or without the .tfvars extension).
+func (e Example) Fixture(name string) (Fixture, bool) {
+	name = strings.TrimSuffix(name, fixtureExtension)
+	for _, f := range e.Fixtures {
+		if f.Name == name {
+			return f, true
+		}
+	}
+
+	return Fixture{}, false
+}
+
+// ListModules returns the sorted names of every module under the modules directory.
+// A directory is considered a module when it contains at least one .tf file.
+func (t *TFSourcesDir) ListModules() ([]string, error) {
+	entries, err := os.ReadDir(t.modulesDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read modules directory %s: %w", t.modulesDir, err)
+	}
+
+	var modules []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		hasTF, err := containsTerraformFiles(filepath.Join(t.modulesDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		if hasTF {
+			modules = append(modules, entry.Name())
+		}
+	}
+
+	sort.Strings(modules)
+
+	return modules, nil
+}
+
+// ListAllExamples returns every example under the examples directory, sorted by path.
+// Examples live at examples/<group>/<example>. An example belongs to the module named after
+// its group directory when such a module exists; otherwise it belongs to the single local module
+// it references through a relative module source.
+func (t *TFSourcesDir) ListAllExamples() ([]Example, error) {
+	modules, err := t.ListModules()
+	if err != nil {
+		return nil, err
+	}
+
+	knownModules := make(map[string]bool, len(modules))
+	for _, m := range modules {
+		knownModules[m] = true
+	}
+
+	groups, err := os.ReadDir(t.examplesDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read examples directory %s: %w", t.examplesDir, err)
+	}
+
+	var examples []Example
+	for _, group := range groups {
+		if !group.IsDir() {
+			continue
+		}
+
+		groupExamples, err := t.listGroupExamples(group.Name(), knownModules)
+		if err != nil {
+			return nil, err
+		}
+
+		examples = append(examples, groupExamples...)
+	}
+
+	sort.Slice(examples, func(i, j int) bool {
+		return examples[i].Path < examples[j].Path
+	})
+
+	return examples, nil
+}
+
+// ListExamples returns the examples that belong to the given module, sorted by path.
+func (t *TFSourcesDir) ListExamples(moduleName string) ([]Example, error) {
+	all, err := t.ListAllExamples()
+	if err != nil {
+		return nil, err
+	}
+
+	var examples []Example
+	for _, e := range all {
+		if e.Module == moduleName {
+			examples = append(examples, e)
+		}
+	}
+
+	return examples, nil
+}
+
+// ListFixtures returns the sorted fixtures in the fixtures/ directory of the given example.
+// The example path is relative to the examples directory (e.g. "domain/basic"). An example
+// without a fixtures/ directory has no fixtures.
+func (t *TFSourcesDir) ListFixtures(examplePath string) ([]Fixture, error) {
+	dir := filepath.Join(t.GetExamplesDir(examplePath), fixturesDir)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to read fixtures directory %s: %w", dir, err)
+	}
+
+	var fixtures []Fixture
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != fixtureExtension {
+			continue
+		}
+
+		fixtures = append(fixtures, Fixture{
+			Name: strings.TrimSuffix(entry.Name(), fixtureExtension),
+			File: entry.Name(),
+			Path: filepath.Join(dir, entry.Name()),
+		})
+	}
+
+	sort.Slice(fixtures, func(i, j int) bool {
+		return fixtures[i].File < fixtures[j].File
+	})
+
+	return fixtures, nil
+}
+
+// listGroupExamples returns the examples under examples/<group>.
+func (t *TFSourcesDir) listGroupExamples(group string, knownModules map[string]bool) ([]Example, error) {
+	entries, err := os.ReadDir(filepath.Join(t.examplesDir, group))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read example group %s: %w", group, err)
+	}
+
+	var examples []Example
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		examplePath := filepath.Join(group, entry.Name())
+		exampleDir := t.GetExamplesDir(examplePath)
+
+		hasTF, err := containsTerraformFiles(exampleDir)
+		if err != nil {
+			return nil, err
+		}
+
+		if !hasTF {
+			continue
+		}
+
+		module := group
+		if !knownModules[module] {
+			module, err = referencedModule(exampleDir)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve the module of example %s: %w", examplePath, err)
+			}
+		}
+
+		fixtures, err := t.ListFixtures(examplePath)
+		if err != nil {
+			return nil, err
+		}
+
+		examples = append(examples, Example{
+			Module:   module,
+			Name:     entry.Name(),
+			Path:     examplePath,
+			Dir:      exampleDir,
+			Fixtures: fixtures,
+		})
+	}
+
+	return examples, nil
+}
+
+// referencedModule returns the single repository module referenced by the .tf files in dir.
+func referencedModule(dir string) (string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return "", err
+	}
+
+	referenced := map[string]bool{}
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", file, err)
+		}
+
+		for _, match := range localModuleSourcePattern.FindAllStringSubmatch(string(content), -1) {
+			referenced[match[1]] = true
+		}
+	}
+
+	if len(referenced) != 1 {
+		names := make([]string, 0, len(referenced))
+		for name := range referenced {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		return "", fmt.Errorf("expected exactly one local module source, found %v", names)
+	}
+
+	for name := range referenced {
+		return name, nil
+	}
+
+	return "", nil
+}
+
+// containsTerraformFiles reports whether dir directly contains at least one .tf file.
+func containsTerraformFiles(dir string) (bool, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return false, fmt.Errorf("failed to list Terraform files in %s: %w", dir, err)
+	}
+
+	return len(files) > 0, nil
+}
@@ -90,3 +90,9 @@ func (t *TFSourcesDir) GetRootDir() string {
 func (t *TFSourcesDir) GetTargetDir(moduleName, targetName string) string {
 	return filepath.Join(t.rootDir, "tests", "modules", moduleName, "target", targetName)
 }
+
+// GetGoldenDir returns the absolute path to the golden plan snapshots of a module test suite.
+// The directory is located at tests/modules/<moduleName>/golden.
+func (t *TFSourcesDir) GetGoldenDir(moduleName string) string {
+	return filepath.Join(t.rootDir, "tests", "modules", moduleName, "golden")
+}
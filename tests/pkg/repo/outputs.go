@@ -0,0 +1,60 @@
+package repo
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Output is an output value declared by a Terraform configuration.
+type Output struct {
+	Name      string
+	Sensitive bool      // Whether the output declares sensitive = true.
+	Range     hcl.Range // The location of the output block.
+}
+
+// ParseOutputs returns the output values declared by the .tf files of a Terraform configuration
+// directory, keyed by name.
+func ParseOutputs(dir string) (map[string]Output, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Terraform files in %s: %w", dir, err)
+	}
+
+	sort.Strings(files)
+
+	parser := hclparse.NewParser()
+	outputs := map[string]Output{}
+
+	for _, path := range files {
+		body, err := parseBody(parser, path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, block := range body.Blocks {
+			if block.Type != "output" || len(block.Labels) != 1 {
+				continue
+			}
+
+			output := Output{Name: block.Labels[0], Range: block.Range()}
+
+			if attr, ok := block.Body.Attributes["sensitive"]; ok {
+				value, diags := attr.Expr.Value(&hcl.EvalContext{})
+				if diags.HasErrors() || value.Type() != cty.Bool || value.IsNull() {
+					return nil, fmt.Errorf("invalid sensitive argument of output %q in %s", output.Name, path)
+				}
+
+				output.Sensitive = value.True()
+			}
+
+			outputs[output.Name] = output
+		}
+	}
+
+	return outputs, nil
+}
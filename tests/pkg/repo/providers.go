@@ -0,0 +1,169 @@
+package repo
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Constants used to discover provider requirements.
+const (
+	versionsFile    = "versions.tf"
+	lockFile        = ".terraform.lock.hcl"
+	defaultRegistry = "hashicorp"
+)
+
+// ProviderRequirement is a single entry of a required_providers block.
+type ProviderRequirement struct {
+	Name    string // The local name of the provider, e.g. "aws".
+	Source  string // The provider source address, e.g. "hashicorp/aws".
+	Version string // The version constraint, empty when none is declared.
+}
+
+// VersionsFile is a versions.tf file and the provider requirements it declares.
+type VersionsFile struct {
+	Dir       string                // The absolute path of the directory holding the file.
+	Path      string                // The absolute path of the versions.tf file.
+	LockFile  string                // The absolute path of the sibling dependency lock file, empty when absent.
+	Providers []ProviderRequirement // The providers declared in required_providers, sorted by name.
+}
+
+// ListVersionsFiles returns every versions.tf under the modules and examples directories with the
+// provider requirements it declares, sorted by path. Terraform working directories are ignored.
+func (t *TFSourcesDir) ListVersionsFiles() ([]VersionsFile, error) {
+	var files []VersionsFile
+
+	for _, root := range []string{t.modulesDir, t.examplesDir} {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if d.IsDir() && d.Name() == ".terraform" {
+				return filepath.SkipDir
+			}
+
+			if d.IsDir() || d.Name() != versionsFile {
+				return nil
+			}
+
+			providers, err := ParseRequiredProviders(path)
+			if err != nil {
+				return err
+			}
+
+			file := VersionsFile{
+				Dir:       filepath.Dir(path),
+				Path:      path,
+				Providers: providers,
+			}
+
+			lock := filepath.Join(file.Dir, lockFile)
+			if _, err := os.Stat(lock); err == nil {
+				file.LockFile = lock
+			}
+
+			files = append(files, file)
+
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover %s files under %s: %w", versionsFile, root, err)
+		}
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+
+	return files, nil
+}
+
+// ParseRequiredProviders returns the providers declared in the required_providers blocks of a
+// Terraform file, sorted by name. Both the object form and the legacy version-string form are
+// supported; a provider without a source defaults to the hashicorp namespace.
+func ParseRequiredProviders(path string) ([]ProviderRequirement, error) {
+	body, err := parseBody(hclparse.NewParser(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	var providers []ProviderRequirement
+
+	for _, terraformBlock := range body.Blocks {
+		if terraformBlock.Type != "terraform" {
+			continue
+		}
+
+		for _, block := range terraformBlock.Body.Blocks {
+			if block.Type != "required_providers" {
+				continue
+			}
+
+			for name, attr := range block.Body.Attributes {
+				provider, err := parseProviderRequirement(name, attr)
+				if err != nil {
+					return nil, fmt.Errorf("invalid required provider %q in %s: %w", name, path, err)
+				}
+
+				providers = append(providers, provider)
+			}
+		}
+	}
+
+	sort.Slice(providers, func(i, j int) bool { return providers[i].Name < providers[j].Name })
+
+	return providers, nil
+}
+
+// parseProviderRequirement decodes a single required_providers attribute.
+func parseProviderRequirement(name string, attr *hclsyntax.Attribute) (ProviderRequirement, error) {
+	provider := ProviderRequirement{
+		Name:   name,
+		Source: defaultRegistry + "/" + name,
+	}
+
+	value, diags := attr.Expr.Value(&hcl.EvalContext{})
+	if diags.HasErrors() {
+		return provider, fmt.Errorf("%s", diags.Error())
+	}
+
+	switch {
+	case value.Type() == cty.String:
+		provider.Version = value.AsString()
+	case value.Type().IsObjectType():
+		if source, ok := stringAttribute(value, "source"); ok {
+			provider.Source = source
+		}
+
+		if version, ok := stringAttribute(value, "version"); ok {
+			provider.Version = version
+		}
+	default:
+		return provider, fmt.Errorf("expected an object or a version string, got %s", value.Type().FriendlyName())
+	}
+
+	provider.Source = strings.ToLower(provider.Source)
+
+	return provider, nil
+}
+
+// stringAttribute returns the string attribute of an object value, if present and known.
+func stringAttribute(value cty.Value, name string) (string, bool) {
+	if !value.Type().HasAttribute(name) {
+		return "", false
+	}
+
+	attr := value.GetAttr(name)
+	if attr.IsNull() || !attr.IsKnown() || attr.Type() != cty.String {
+		return "", false
+	}
+
+	return attr.AsString(), true
+}
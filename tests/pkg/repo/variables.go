@@ -0,0 +1,165 @@
+package repo
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Variable is an input variable declared by a Terraform configuration.
+type Variable struct {
+	Name        string
+	Type        cty.Type     // The type constraint; cty.DynamicPseudoType when the variable declares none.
+	Default     cty.Value    // cty.NilVal when the variable declares no default.
+	Validations []Validation // The validation blocks, in declaration order.
+	Range       hcl.Range    // The location of the variable block.
+}
+
+// Validation is a validation block of an input variable.
+type Validation struct {
+	// ErrorMessage is the message Terraform reports when the condition fails. A message that is not a
+	// constant string is kept as its source text.
+	ErrorMessage string
+	Range        hcl.Range // The location of the validation block.
+}
+
+// Required reports whether the variable must be assigned, i.e. declares no default.
+func (v Variable) Required() bool {
+	return v.Default == cty.NilVal
+}
+
+// ParseVariables returns the input variables declared by the .tf files of a Terraform
+// configuration directory, keyed by name.
+func ParseVariables(dir string) (map[string]Variable, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Terraform files in %s: %w", dir, err)
+	}
+
+	sort.Strings(files)
+
+	parser := hclparse.NewParser()
+	variables := map[string]Variable{}
+
+	for _, path := range files {
+		body, err := parseBody(parser, path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, block := range body.Blocks {
+			if block.Type != "variable" || len(block.Labels) != 1 {
+				continue
+			}
+
+			variable := Variable{Name: block.Labels[0], Type: cty.DynamicPseudoType, Range: block.Range()}
+
+			if attr, ok := block.Body.Attributes["type"]; ok {
+				typ, _, diags := typeexpr.TypeConstraintWithDefaults(attr.Expr)
+				if diags.HasErrors() {
+					return nil, fmt.Errorf("invalid type of variable %q in %s: %s", variable.Name, path, diags.Error())
+				}
+
+				variable.Type = typ
+			}
+
+			if attr, ok := block.Body.Attributes["default"]; ok {
+				value, diags := attr.Expr.Value(&hcl.EvalContext{})
+				if diags.HasErrors() {
+					return nil, fmt.Errorf("invalid default of variable %q in %s: %s", variable.Name, path, diags.Error())
+				}
+
+				variable.Default = value
+			}
+
+			for _, nested := range block.Body.Blocks {
+				if nested.Type != "validation" {
+					continue
+				}
+
+				variable.Validations = append(variable.Validations, Validation{
+					ErrorMessage: errorMessage(parser, nested.Body),
+					Range:        nested.Range(),
+				})
+			}
+
+			variables[variable.Name] = variable
+		}
+	}
+
+	return variables, nil
+}
+
+// Assignment is a variable value assigned by a .tfvars file.
+type Assignment struct {
+	Name  string
+	Expr  hcl.Expression
+	Range hcl.Range // The location of the whole assignment.
+}
+
+// Value evaluates the assignment the way Terraform does, without variables or functions.
+func (a Assignment) Value() (cty.Value, error) {
+	value, diags := a.Expr.Value(&hcl.EvalContext{})
+	if diags.HasErrors() {
+		return cty.NilVal, fmt.Errorf("invalid value of %q: %s", a.Name, diags.Error())
+	}
+
+	return value, nil
+}
+
+// ParseVarFile returns the assignments of a .tfvars file, keyed by variable name. Values are
+// evaluated on demand, so one invalid value does not hide the others.
+func ParseVarFile(path string) (map[string]Assignment, error) {
+	body, err := parseBody(hclparse.NewParser(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	assignments := make(map[string]Assignment, len(body.Attributes))
+
+	for name, attr := range body.Attributes {
+		assignments[name] = Assignment{Name: name, Expr: attr.Expr, Range: attr.Range()}
+	}
+
+	return assignments, nil
+}
+
+// errorMessage returns the error_message of a validation block.
+func errorMessage(parser *hclparse.Parser, body *hclsyntax.Body) string {
+	attr, ok := body.Attributes["error_message"]
+	if !ok {
+		return ""
+	}
+
+	if value, diags := attr.Expr.Value(&hcl.EvalContext{}); !diags.HasErrors() && value.Type() == cty.String && value.IsKnown() && !value.IsNull() {
+		return value.AsString()
+	}
+
+	exprRange := attr.Expr.Range()
+	if file, ok := parser.Files()[exprRange.Filename]; ok {
+		return string(exprRange.SliceBytes(file.Bytes))
+	}
+
+	return ""
+}
+
+// parseBody parses a native-syntax HCL file.
+func parseBody(parser *hclparse.Parser, path string) (*hclsyntax.Body, error) {
+	parsed, diags := parser.ParseHCLFile(path)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("failed to parse %s: %s", path, diags.Error())
+	}
+
+	body, ok := parsed.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, fmt.Errorf("unexpected body type in %s", path)
+	}
+
+	return body, nil
+}
@@ -0,0 +1,199 @@
+package sweeper
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/codeartifact"
+	codeartifacttypes "github.com/aws/aws-sdk-go-v2/service/codeartifact/types"
+)
+
+// domain is a CodeArtifact domain selected for sweeping.
+type domain struct {
+	name      string
+	owner     string
+	runID     string
+	createdAt time.Time
+}
+
+// findRepositories returns the tagged repositories and every repository of a swept domain, which
+// must be deleted before the domain itself.
+func (s *Sweeper) findRepositories(ctx context.Context) ([]Resource, error) {
+	domains, err := s.selectedDomains(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	swept := map[string]bool{}
+	for _, d := range domains {
+		swept[d.owner+"/"+d.name] = true
+	}
+
+	var resources []Resource
+
+	paginator := codeartifact.NewListRepositoriesPaginator(s.codeArtifact, &codeartifact.ListRepositoriesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, repo := range page.Repositories {
+			domainName, owner, name := aws.ToString(repo.DomainName), aws.ToString(repo.DomainOwner), aws.ToString(repo.Name)
+
+			runID, ok := "", swept[owner+"/"+domainName]
+			if !ok {
+				tags, err := s.codeArtifactTags(ctx, aws.ToString(repo.Arn))
+				if err != nil {
+					return nil, err
+				}
+
+				if runID, ok = s.selected(tags, aws.ToTime(repo.CreatedTime)); !ok {
+					continue
+				}
+			}
+
+			resources = append(resources, Resource{
+				Kind:      "CodeArtifact repository",
+				ID:        domainName + "/" + name,
+				RunID:     runID,
+				CreatedAt: aws.ToTime(repo.CreatedTime),
+				delete: func(ctx context.Context) error {
+					_, err := s.codeArtifact.DeleteRepository(ctx, &codeartifact.DeleteRepositoryInput{
+						Domain:      aws.String(domainName),
+						DomainOwner: aws.String(owner),
+						Repository:  aws.String(name),
+					})
+
+					return err
+				},
+			})
+		}
+	}
+
+	return resources, nil
+}
+
+// findDomainPolicies returns the resource policies of the swept domains.
+func (s *Sweeper) findDomainPolicies(ctx context.Context) ([]Resource, error) {
+	domains, err := s.selectedDomains(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []Resource
+
+	for _, d := range domains {
+		_, err := s.codeArtifact.GetDomainPermissionsPolicy(ctx, &codeartifact.GetDomainPermissionsPolicyInput{
+			Domain:      aws.String(d.name),
+			DomainOwner: aws.String(d.owner),
+		})
+		if isNotFound(err) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		resources = append(resources, Resource{
+			Kind:      "CodeArtifact domain policy",
+			ID:        d.name,
+			RunID:     d.runID,
+			CreatedAt: d.createdAt,
+			delete: func(ctx context.Context) error {
+				_, err := s.codeArtifact.DeleteDomainPermissionsPolicy(ctx, &codeartifact.DeleteDomainPermissionsPolicyInput{
+					Domain:      aws.String(d.name),
+					DomainOwner: aws.String(d.owner),
+				})
+
+				return err
+			},
+		})
+	}
+
+	return resources, nil
+}
+
+// findDomains returns the swept domains.
+func (s *Sweeper) findDomains(ctx context.Context) ([]Resource, error) {
+	domains, err := s.selectedDomains(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]Resource, 0, len(domains))
+
+	for _, d := range domains {
+		resources = append(resources, Resource{
+			Kind:      "CodeArtifact domain",
+			ID:        d.name,
+			RunID:     d.runID,
+			CreatedAt: d.createdAt,
+			delete: func(ctx context.Context) error {
+				_, err := s.codeArtifact.DeleteDomain(ctx, &codeartifact.DeleteDomainInput{
+					Domain:      aws.String(d.name),
+					DomainOwner: aws.String(d.owner),
+				})
+
+				return err
+			},
+		})
+	}
+
+	return resources, nil
+}
+
+// selectedDomains lists the domains that carry the run ID tag and are old enough to be swept.
+func (s *Sweeper) selectedDomains(ctx context.Context) ([]domain, error) {
+	var domains []domain
+
+	paginator := codeartifact.NewListDomainsPaginator(s.codeArtifact, &codeartifact.ListDomainsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, d := range page.Domains {
+			if d.Status == codeartifacttypes.DomainStatusDeleted {
+				continue
+			}
+
+			tags, err := s.codeArtifactTags(ctx, aws.ToString(d.Arn))
+			if err != nil {
+				return nil, err
+			}
+
+			runID, ok := s.selected(tags, aws.ToTime(d.CreatedTime))
+			if !ok {
+				continue
+			}
+
+			domains = append(domains, domain{
+				name:      aws.ToString(d.Name),
+				owner:     aws.ToString(d.Owner),
+				runID:     runID,
+				createdAt: aws.ToTime(d.CreatedTime),
+			})
+		}
+	}
+
+	return domains, nil
+}
+
+// codeArtifactTags returns the tags of a CodeArtifact domain or repository.
+func (s *Sweeper) codeArtifactTags(ctx context.Context, resourceARN string) (map[string]string, error) {
+	out, err := s.codeArtifact.ListTagsForResource(ctx, &codeartifact.ListTagsForResourceInput{ResourceArn: aws.String(resourceARN)})
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make(map[string]string, len(out.Tags))
+	for _, tag := range out.Tags {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+
+	return tags, nil
+}
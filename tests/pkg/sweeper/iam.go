@@ -0,0 +1,282 @@
+package sweeper
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// serviceRolePath is the path of the roles managed by AWS services, which are never swept.
+const serviceRolePath = "/aws-service-role/"
+
+// findRoles returns the tagged IAM roles, such as the OIDC roles of the permissions modules.
+func (s *Sweeper) findRoles(ctx context.Context) ([]Resource, error) {
+	var resources []Resource
+
+	paginator := iam.NewListRolesPaginator(s.iam, &iam.ListRolesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, role := range page.Roles {
+			if strings.HasPrefix(aws.ToString(role.Path), serviceRolePath) {
+				continue
+			}
+
+			name := aws.ToString(role.RoleName)
+
+			tags, err := s.roleTags(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+
+			runID, ok := s.selected(tags, aws.ToTime(role.CreateDate))
+			if !ok {
+				continue
+			}
+
+			resources = append(resources, Resource{
+				Kind:      "IAM role",
+				ID:        name,
+				RunID:     runID,
+				CreatedAt: aws.ToTime(role.CreateDate),
+				delete: func(ctx context.Context) error {
+					return s.deleteRole(ctx, name)
+				},
+			})
+		}
+	}
+
+	return resources, nil
+}
+
+// roleTags returns the tags of an IAM role.
+func (s *Sweeper) roleTags(ctx context.Context, name string) (map[string]string, error) {
+	tags := map[string]string{}
+
+	paginator := iam.NewListRoleTagsPaginator(s.iam, &iam.ListRoleTagsInput{RoleName: aws.String(name)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		addIAMTags(tags, page.Tags)
+	}
+
+	return tags, nil
+}
+
+// deleteRole detaches the managed policies of a role, deletes its inline policies, removes it
+// from its instance profiles and deletes it.
+func (s *Sweeper) deleteRole(ctx context.Context, name string) error {
+	attached := iam.NewListAttachedRolePoliciesPaginator(s.iam, &iam.ListAttachedRolePoliciesInput{RoleName: aws.String(name)})
+	for attached.HasMorePages() {
+		page, err := attached.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, policy := range page.AttachedPolicies {
+			if _, err := s.iam.DetachRolePolicy(ctx, &iam.DetachRolePolicyInput{RoleName: aws.String(name), PolicyArn: policy.PolicyArn}); err != nil {
+				return err
+			}
+		}
+	}
+
+	inline := iam.NewListRolePoliciesPaginator(s.iam, &iam.ListRolePoliciesInput{RoleName: aws.String(name)})
+	for inline.HasMorePages() {
+		page, err := inline.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, policy := range page.PolicyNames {
+			if _, err := s.iam.DeleteRolePolicy(ctx, &iam.DeleteRolePolicyInput{RoleName: aws.String(name), PolicyName: aws.String(policy)}); err != nil {
+				return err
+			}
+		}
+	}
+
+	profiles := iam.NewListInstanceProfilesForRolePaginator(s.iam, &iam.ListInstanceProfilesForRoleInput{RoleName: aws.String(name)})
+	for profiles.HasMorePages() {
+		page, err := profiles.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, profile := range page.InstanceProfiles {
+			if _, err := s.iam.RemoveRoleFromInstanceProfile(ctx, &iam.RemoveRoleFromInstanceProfileInput{
+				RoleName:            aws.String(name),
+				InstanceProfileName: profile.InstanceProfileName,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := s.iam.DeleteRole(ctx, &iam.DeleteRoleInput{RoleName: aws.String(name)})
+
+	return err
+}
+
+// findPolicies returns the tagged customer managed IAM policies.
+func (s *Sweeper) findPolicies(ctx context.Context) ([]Resource, error) {
+	var resources []Resource
+
+	paginator := iam.NewListPoliciesPaginator(s.iam, &iam.ListPoliciesInput{Scope: iamtypes.PolicyScopeTypeLocal})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, policy := range page.Policies {
+			policyARN := aws.ToString(policy.Arn)
+
+			tags, err := s.policyTags(ctx, policyARN)
+			if err != nil {
+				return nil, err
+			}
+
+			runID, ok := s.selected(tags, aws.ToTime(policy.CreateDate))
+			if !ok {
+				continue
+			}
+
+			resources = append(resources, Resource{
+				Kind:      "IAM policy",
+				ID:        policyARN,
+				RunID:     runID,
+				CreatedAt: aws.ToTime(policy.CreateDate),
+				delete: func(ctx context.Context) error {
+					return s.deletePolicy(ctx, policyARN)
+				},
+			})
+		}
+	}
+
+	return resources, nil
+}
+
+// policyTags returns the tags of a customer managed IAM policy.
+func (s *Sweeper) policyTags(ctx context.Context, policyARN string) (map[string]string, error) {
+	tags := map[string]string{}
+
+	paginator := iam.NewListPolicyTagsPaginator(s.iam, &iam.ListPolicyTagsInput{PolicyArn: aws.String(policyARN)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		addIAMTags(tags, page.Tags)
+	}
+
+	return tags, nil
+}
+
+// deletePolicy detaches a managed policy from every role, user and group, deletes its non-default
+// versions and deletes it.
+func (s *Sweeper) deletePolicy(ctx context.Context, policyARN string) error {
+	entities := iam.NewListEntitiesForPolicyPaginator(s.iam, &iam.ListEntitiesForPolicyInput{PolicyArn: aws.String(policyARN)})
+	for entities.HasMorePages() {
+		page, err := entities.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, role := range page.PolicyRoles {
+			if _, err := s.iam.DetachRolePolicy(ctx, &iam.DetachRolePolicyInput{RoleName: role.RoleName, PolicyArn: aws.String(policyARN)}); err != nil {
+				return err
+			}
+		}
+
+		for _, user := range page.PolicyUsers {
+			if _, err := s.iam.DetachUserPolicy(ctx, &iam.DetachUserPolicyInput{UserName: user.UserName, PolicyArn: aws.String(policyARN)}); err != nil {
+				return err
+			}
+		}
+
+		for _, group := range page.PolicyGroups {
+			if _, err := s.iam.DetachGroupPolicy(ctx, &iam.DetachGroupPolicyInput{GroupName: group.GroupName, PolicyArn: aws.String(policyARN)}); err != nil {
+				return err
+			}
+		}
+	}
+
+	versions := iam.NewListPolicyVersionsPaginator(s.iam, &iam.ListPolicyVersionsInput{PolicyArn: aws.String(policyARN)})
+	for versions.HasMorePages() {
+		page, err := versions.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, version := range page.Versions {
+			if version.IsDefaultVersion {
+				continue
+			}
+
+			if _, err := s.iam.DeletePolicyVersion(ctx, &iam.DeletePolicyVersionInput{PolicyArn: aws.String(policyARN), VersionId: version.VersionId}); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := s.iam.DeletePolicy(ctx, &iam.DeletePolicyInput{PolicyArn: aws.String(policyARN)})
+
+	return err
+}
+
+// findOIDCProviders returns the tagged IAM OpenID Connect providers.
+func (s *Sweeper) findOIDCProviders(ctx context.Context) ([]Resource, error) {
+	out, err := s.iam.ListOpenIDConnectProviders(ctx, &iam.ListOpenIDConnectProvidersInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []Resource
+
+	for _, entry := range out.OpenIDConnectProviderList {
+		providerARN := aws.ToString(entry.Arn)
+
+		provider, err := s.iam.GetOpenIDConnectProvider(ctx, &iam.GetOpenIDConnectProviderInput{OpenIDConnectProviderArn: aws.String(providerARN)})
+		if err != nil {
+			return nil, err
+		}
+
+		tags := map[string]string{}
+		addIAMTags(tags, provider.Tags)
+
+		runID, ok := s.selected(tags, aws.ToTime(provider.CreateDate))
+		if !ok {
+			continue
+		}
+
+		resources = append(resources, Resource{
+			Kind:      "IAM OIDC provider",
+			ID:        providerARN,
+			RunID:     runID,
+			CreatedAt: aws.ToTime(provider.CreateDate),
+			delete: func(ctx context.Context) error {
+				_, err := s.iam.DeleteOpenIDConnectProvider(ctx, &iam.DeleteOpenIDConnectProviderInput{OpenIDConnectProviderArn: aws.String(providerARN)})
+
+				return err
+			},
+		})
+	}
+
+	return resources, nil
+}
+
+// addIAMTags adds IAM tags to a tag map.
+func addIAMTags(tags map[string]string, iamTags []iamtypes.Tag) {
+	for _, tag := range iamTags {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+}
@@ -0,0 +1,273 @@
+package sweeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+// keyDeletionWindowDays is the shortest waiting period KMS accepts before deleting a key.
+const keyDeletionWindowDays = 7
+
+// findBuckets returns the tagged S3 buckets of the region.
+func (s *Sweeper) findBuckets(ctx context.Context) ([]Resource, error) {
+	var resources []Resource
+
+	paginator := s3.NewListBucketsPaginator(s.s3, &s3.ListBucketsInput{BucketRegion: aws.String(s.region)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, bucket := range page.Buckets {
+			name := aws.ToString(bucket.Name)
+
+			tags, err := s.bucketTags(ctx, name)
+			if isNotFound(err) {
+				continue
+			}
+
+			if err != nil {
+				return nil, fmt.Errorf("failed to read tags of bucket %s: %w", name, err)
+			}
+
+			runID, ok := s.selected(tags, aws.ToTime(bucket.CreationDate))
+			if !ok {
+				continue
+			}
+
+			resources = append(resources, Resource{
+				Kind:      "S3 bucket",
+				ID:        name,
+				RunID:     runID,
+				CreatedAt: aws.ToTime(bucket.CreationDate),
+				delete: func(ctx context.Context) error {
+					if err := s.emptyBucket(ctx, name); err != nil {
+						return err
+					}
+
+					_, err := s.s3.DeleteBucket(ctx, &s3.DeleteBucketInput{Bucket: aws.String(name)})
+
+					return err
+				},
+			})
+		}
+	}
+
+	return resources, nil
+}
+
+// bucketTags returns the tags of a bucket; a bucket without tags has an empty tag set.
+func (s *Sweeper) bucketTags(ctx context.Context, bucket string) (map[string]string, error) {
+	out, err := s.s3.GetBucketTagging(ctx, &s3.GetBucketTaggingInput{Bucket: aws.String(bucket)})
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchTagSet" {
+		return map[string]string{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make(map[string]string, len(out.TagSet))
+	for _, tag := range out.TagSet {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+
+	return tags, nil
+}
+
+// emptyBucket deletes every object version and delete marker of a bucket, so it can be deleted.
+func (s *Sweeper) emptyBucket(ctx context.Context, bucket string) error {
+	input := &s3.ListObjectVersionsInput{Bucket: aws.String(bucket)}
+
+	for {
+		page, err := s.s3.ListObjectVersions(ctx, input)
+		if err != nil {
+			return err
+		}
+
+		objects := make([]s3types.ObjectIdentifier, 0, len(page.Versions)+len(page.DeleteMarkers))
+		for _, v := range page.Versions {
+			objects = append(objects, s3types.ObjectIdentifier{Key: v.Key, VersionId: v.VersionId})
+		}
+
+		for _, m := range page.DeleteMarkers {
+			objects = append(objects, s3types.ObjectIdentifier{Key: m.Key, VersionId: m.VersionId})
+		}
+
+		if len(objects) > 0 {
+			out, err := s.s3.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+				Bucket: aws.String(bucket),
+				Delete: &s3types.Delete{Objects: objects, Quiet: aws.Bool(true)},
+			})
+			if err != nil {
+				return err
+			}
+
+			if len(out.Errors) > 0 {
+				return fmt.Errorf("failed to delete %d object(s), e.g. %s: %s",
+					len(out.Errors), aws.ToString(out.Errors[0].Key), aws.ToString(out.Errors[0].Message))
+			}
+		}
+
+		if !aws.ToBool(page.IsTruncated) {
+			return nil
+		}
+
+		input.KeyMarker = page.NextKeyMarker
+		input.VersionIdMarker = page.NextVersionIdMarker
+	}
+}
+
+// findKeys returns the tagged customer managed KMS keys that are not already pending deletion.
+func (s *Sweeper) findKeys(ctx context.Context) ([]Resource, error) {
+	var resources []Resource
+
+	paginator := kms.NewListKeysPaginator(s.kms, &kms.ListKeysInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range page.Keys {
+			keyID := aws.ToString(entry.KeyId)
+
+			out, err := s.kms.DescribeKey(ctx, &kms.DescribeKeyInput{KeyId: aws.String(keyID)})
+			if err != nil {
+				return nil, err
+			}
+
+			key := out.KeyMetadata
+			if key.KeyManager != kmstypes.KeyManagerTypeCustomer ||
+				key.KeyState == kmstypes.KeyStatePendingDeletion ||
+				key.KeyState == kmstypes.KeyStatePendingReplicaDeletion {
+				continue
+			}
+
+			tags, err := s.keyTags(ctx, keyID)
+			if err != nil {
+				return nil, err
+			}
+
+			runID, ok := s.selected(tags, aws.ToTime(key.CreationDate))
+			if !ok {
+				continue
+			}
+
+			resources = append(resources, Resource{
+				Kind:      "KMS key",
+				ID:        keyID,
+				RunID:     runID,
+				CreatedAt: aws.ToTime(key.CreationDate),
+				delete: func(ctx context.Context) error {
+					return s.deleteKey(ctx, keyID)
+				},
+			})
+		}
+	}
+
+	return resources, nil
+}
+
+// keyTags returns the tags of a KMS key.
+func (s *Sweeper) keyTags(ctx context.Context, keyID string) (map[string]string, error) {
+	tags := map[string]string{}
+
+	paginator := kms.NewListResourceTagsPaginator(s.kms, &kms.ListResourceTagsInput{KeyId: aws.String(keyID)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, tag := range page.Tags {
+			tags[aws.ToString(tag.TagKey)] = aws.ToString(tag.TagValue)
+		}
+	}
+
+	return tags, nil
+}
+
+// deleteKey deletes the aliases of a KMS key, which would keep their names taken until the key is
+// gone, and schedules the key for deletion.
+func (s *Sweeper) deleteKey(ctx context.Context, keyID string) error {
+	paginator := kms.NewListAliasesPaginator(s.kms, &kms.ListAliasesInput{KeyId: aws.String(keyID)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, alias := range page.Aliases {
+			if _, err := s.kms.DeleteAlias(ctx, &kms.DeleteAliasInput{AliasName: alias.AliasName}); err != nil && !isNotFound(err) {
+				return err
+			}
+		}
+	}
+
+	_, err := s.kms.ScheduleKeyDeletion(ctx, &kms.ScheduleKeyDeletionInput{
+		KeyId:               aws.String(keyID),
+		PendingWindowInDays: aws.Int32(keyDeletionWindowDays),
+	})
+
+	return err
+}
+
+// findLogGroups returns the tagged CloudWatch log groups of the region.
+func (s *Sweeper) findLogGroups(ctx context.Context) ([]Resource, error) {
+	var resources []Resource
+
+	paginator := cloudwatchlogs.NewDescribeLogGroupsPaginator(s.logs, &cloudwatchlogs.DescribeLogGroupsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, group := range page.LogGroups {
+			name := aws.ToString(group.LogGroupName)
+
+			out, err := s.logs.ListTagsForResource(ctx, &cloudwatchlogs.ListTagsForResourceInput{ResourceArn: group.LogGroupArn})
+			if err != nil {
+				return nil, err
+			}
+
+			var createdAt time.Time
+			if group.CreationTime != nil {
+				createdAt = time.UnixMilli(*group.CreationTime)
+			}
+
+			runID, ok := s.selected(out.Tags, createdAt)
+			if !ok {
+				continue
+			}
+
+			resources = append(resources, Resource{
+				Kind:      "CloudWatch log group",
+				ID:        name,
+				RunID:     runID,
+				CreatedAt: createdAt,
+				delete: func(ctx context.Context) error {
+					_, err := s.logs.DeleteLogGroup(ctx, &cloudwatchlogs.DeleteLogGroupInput{LogGroupName: aws.String(name)})
+
+					return err
+				},
+			})
+		}
+	}
+
+	return resources, nil
+}
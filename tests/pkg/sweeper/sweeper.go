@@ -0,0 +1,211 @@
+package sweeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/codeartifact"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+)
+
+// Options selects the resources to sweep.
+type Options struct {
+	OlderThan     time.Duration // Only resources created at least this long ago are swept.
+	RunID         string        // Only resources of this test run are swept; empty sweeps every run.
+	DryRun        bool          // List the resources that would be swept without deleting them.
+	IncludeGlobal bool          // Also sweep IAM roles, policies and OIDC providers, which are global.
+}
+
+// Resource is a leaked resource found by the sweeper.
+type Resource struct {
+	Kind      string    // The kind of resource, e.g. "S3 bucket".
+	ID        string    // The identifier of the resource, e.g. the bucket name.
+	RunID     string    // The value of the run ID tag, empty for resources swept with their parent.
+	CreatedAt time.Time // The creation time, zero when AWS does not report it.
+
+	delete func(ctx context.Context) error
+}
+
+// String returns a human-readable description of the resource.
+func (r Resource) String() string {
+	return r.Kind + " " + r.ID
+}
+
+// Sweeper deletes the resources left behind by test runs in one region.
+type Sweeper struct {
+	region string
+	opts   Options
+	now    time.Time
+
+	codeArtifact *codeartifact.Client
+	s3           *s3.Client
+	kms          *kms.Client
+	logs         *cloudwatchlogs.Client
+	iam          *iam.Client
+}
+
+// phase finds the resources of one kind. Phases run in dependency order, so each one only sees
+// what the previous phases left.
+type phase struct {
+	name string
+	find func(ctx context.Context) ([]Resource, error)
+}
+
+// New returns a sweeper for the region of cfg. The S3 options are applied to the S3 client, e.g.
+// to enable path-style addressing against a local emulator.
+func New(cfg aws.Config, opts Options, s3Opts ...func(*s3.Options)) *Sweeper {
+	return &Sweeper{
+		region:       cfg.Region,
+		opts:         opts,
+		now:          time.Now(),
+		codeArtifact: codeartifact.NewFromConfig(cfg),
+		s3:           s3.NewFromConfig(cfg, s3Opts...),
+		kms:          kms.NewFromConfig(cfg),
+		logs:         cloudwatchlogs.NewFromConfig(cfg),
+		iam:          iam.NewFromConfig(cfg),
+	}
+}
+
+// Run finds and deletes the leaked resources phase by phase: CodeArtifact repositories, domain
+// policies and domains, then S3 buckets, KMS keys and log groups, then the global IAM resources.
+// It returns the resources found and the joined errors of the deletions that failed.
+func (s *Sweeper) Run(ctx context.Context) ([]Resource, error) {
+	phases := []phase{
+		{name: "CodeArtifact repositories", find: s.findRepositories},
+		{name: "CodeArtifact domain policies", find: s.findDomainPolicies},
+		{name: "CodeArtifact domains", find: s.findDomains},
+		{name: "S3 buckets", find: s.findBuckets},
+		{name: "KMS keys", find: s.findKeys},
+		{name: "CloudWatch log groups", find: s.findLogGroups},
+	}
+
+	if s.opts.IncludeGlobal {
+		phases = append(phases,
+			phase{name: "IAM roles", find: s.findRoles},
+			phase{name: "IAM policies", find: s.findPolicies},
+			phase{name: "IAM OIDC providers", find: s.findOIDCProviders},
+		)
+	}
+
+	var (
+		swept []Resource
+		errs  []error
+	)
+
+	for _, p := range phases {
+		found, err := p.find(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to list %s in %s: %w", p.name, s.region, err))
+			continue
+		}
+
+		swept = append(swept, found...)
+
+		for _, r := range found {
+			log.Printf("🧹 %s: %s (run %s, created %s)", s.region, r, valueOr(r.RunID, "-"), formatTime(r.CreatedAt))
+		}
+
+		if s.opts.DryRun {
+			continue
+		}
+
+		errs = append(errs, s.deleteAll(ctx, found)...)
+	}
+
+	return swept, errors.Join(errs...)
+}
+
+// deleteAll deletes resources in passes: a resource that fails, such as a repository that is
+// still the upstream of another, is retried as long as the previous pass deleted something.
+func (s *Sweeper) deleteAll(ctx context.Context, resources []Resource) []error {
+	pending := resources
+
+	for len(pending) > 0 {
+		var (
+			failed []Resource
+			errs   []error
+		)
+
+		for _, r := range pending {
+			if err := r.delete(ctx); err != nil && !isNotFound(err) {
+				failed = append(failed, r)
+				errs = append(errs, fmt.Errorf("failed to delete %s in %s: %w", r, s.region, err))
+
+				continue
+			}
+
+			log.Printf("🗑️  %s: deleted %s", s.region, r)
+		}
+
+		if len(failed) == len(pending) {
+			return errs
+		}
+
+		pending = failed
+	}
+
+	return nil
+}
+
+// selected reports whether a resource with the given tags and creation time must be swept: it
+// carries the run ID tag, matches Options.RunID and is either older than Options.OlderThan or past
+// the time of its expiry tag. Resources without a creation time or expiry are only swept when no
+// age threshold is set.
+func (s *Sweeper) selected(tags map[string]string, createdAt time.Time) (string, bool) {
+	runID, tagged := tags[helper.RunIDTagKey]
+	if !tagged || (s.opts.RunID != "" && runID != s.opts.RunID) {
+		return "", false
+	}
+
+	if expiresAt, err := time.Parse(time.RFC3339, tags[helper.ExpiresAtTagKey]); err == nil && s.now.After(expiresAt) {
+		return runID, true
+	}
+
+	if createdAt.IsZero() {
+		return runID, s.opts.OlderThan == 0
+	}
+
+	return runID, s.now.Sub(createdAt) >= s.opts.OlderThan
+}
+
+// isNotFound reports whether err means the resource is already gone.
+func isNotFound(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	switch apiErr.ErrorCode() {
+	case "ResourceNotFoundException", "NoSuchBucket", "NotFoundException", "NoSuchEntity", "NotFound":
+		return true
+	default:
+		return false
+	}
+}
+
+// valueOr returns value, or fallback when value is empty.
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+
+	return value
+}
+
+// formatTime renders a creation time, or "unknown" when it is not reported.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
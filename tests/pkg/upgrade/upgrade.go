@@ -0,0 +1,269 @@
+// Package upgrade checks that consumers can move from the last release of a module to the working
+// tree without destroying or replacing their stateful resources.
+package upgrade
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/harness"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/plan"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/repo"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/waiter"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+// FromRefEnvVar names the git ref to upgrade from, overriding the last release tag of the module.
+const FromRefEnvVar = "TF_TEST_UPGRADE_FROM_REF"
+
+// stateFileName is the local state file Terraform writes in the working directory.
+const stateFileName = "terraform.tfstate"
+
+// ProtectedTypes are the resource types whose destruction loses data or breaks consumers: packages,
+// artifacts and everything encrypted with the key.
+var ProtectedTypes = []string{
+	"aws_codeartifact_domain",
+	"aws_codeartifact_repository",
+	"aws_s3_bucket",
+	"aws_kms_key",
+}
+
+// Config describes an upgrade-path run.
+type Config struct {
+	// Module selects the release tags, "<module>-v<version>", to upgrade from.
+	Module string
+
+	// Example is the example to apply, relative to the examples directory (e.g. "domain/basic").
+	Example string
+
+	// VarFiles are the fixtures passed to both plans, relative to the example directory.
+	VarFiles []string
+
+	// Vars are input variables passed to both plans, e.g. unique resource names.
+	Vars map[string]interface{}
+}
+
+// Run applies the example of the module's last release, then plans the working tree example against
+// its state, and fails if any protected resource is planned for destroy or replacement. The release
+// is the ref in TF_TEST_UPGRADE_FROM_REF or the last "<module>-v*" tag; the test is skipped when
+// there is none, or when the example, its fixtures or the variables in Vars did not exist in that
+// release. The applied resources are destroyed with the release configuration when the test
+// completes.
+func Run(t *testing.T, cfg Config, awsCtx harness.Context) {
+	t.Helper()
+
+	dirs, err := repo.NewTFSourcesDir()
+	require.NoError(t, err, "Failed to get Terraform sources directory")
+
+	ref, err := FromRef(dirs.GetRootDir(), cfg.Module)
+	require.NoError(t, err, "Failed to resolve the release of module %s", cfg.Module)
+
+	if ref == "" {
+		t.Skipf("⏭️ Module %s has no release tag to upgrade from; set %s to choose a ref", cfg.Module, FromRefEnvVar)
+	}
+
+	releaseRoot := Export(t, dirs.GetRootDir(), ref)
+	releaseExample := filepath.Join(releaseRoot, "examples", cfg.Example)
+
+	if _, err := os.Stat(releaseExample); err != nil {
+		t.Skipf("⏭️ Example %s does not exist in %s", cfg.Example, ref)
+	}
+
+	for _, varFile := range cfg.VarFiles {
+		if _, err := os.Stat(filepath.Join(releaseExample, varFile)); err != nil {
+			t.Skipf("⏭️ Example %s has no %s in %s", cfg.Example, varFile, ref)
+		}
+	}
+
+	// Without its unique names, the release would apply the same resources as every other run
+	releaseVariables, err := repo.ParseVariables(releaseExample)
+	require.NoError(t, err, "Failed to parse the variables of example %s in %s", cfg.Example, ref)
+
+	for name := range cfg.Vars {
+		if _, ok := releaseVariables[name]; !ok {
+			t.Skipf("⏭️ Example %s does not declare variable %s in %s", cfg.Example, name, ref)
+		}
+	}
+
+	t.Logf("⏪ Upgrading example %s from %s to the working tree", cfg.Example, ref)
+
+	releaseOptions := newOptions(t, helper.ExampleSource(releaseExample), cfg, awsCtx)
+
+	// Destroy resources when the test completes and wait until AWS reports them deleted
+	defer waiter.DestroyAndWait(t, releaseOptions, awsCtx.Region)
+
+	helper.Init(t, releaseOptions)
+	terraform.Apply(t, releaseOptions)
+
+	currentOptions := newOptions(t, helper.ExampleSource(cfg.Example), cfg, awsCtx, helper.WithWorkspaceCopy())
+	copyState(t, releaseOptions.TerraformDir, currentOptions.TerraformDir)
+
+	t.Logf("🔍 Planning the working tree at: %s", currentOptions.TerraformDir)
+
+	RequireNoDestructiveChanges(t, plan.InitAndPlan(t, currentOptions), ProtectedTypes...)
+}
+
+// RequireNoDestructiveChanges fails the test if any managed resource of the given types is planned
+// for destroy or replacement, listing each with the reason Terraform gives.
+func RequireNoDestructiveChanges(t *testing.T, p *plan.Plan, resourceTypes ...string) {
+	t.Helper()
+
+	var destructive []string
+
+	for _, resourceType := range resourceTypes {
+		for _, rc := range p.ResourceChangesByType(resourceType) {
+			if !rc.Change.IsDelete() && !rc.Change.IsReplace() {
+				continue
+			}
+
+			detail := fmt.Sprintf("%s (%s)", rc.Address, rc.Change.ActionString())
+			if rc.ActionReason != "" {
+				detail += " because " + rc.ActionReason
+			}
+
+			destructive = append(destructive, detail)
+		}
+	}
+
+	require.Emptyf(t, destructive,
+		"Upgrading should not destroy or replace %v; add a moved block for changed addresses", resourceTypes)
+
+	t.Logf("✅ Upgrade plan keeps every %v resource", resourceTypes)
+}
+
+// FromRef returns the ref to upgrade from: TF_TEST_UPGRADE_FROM_REF, or the last "<module>-v*" tag
+// by version, or "" when the module has no release.
+func FromRef(rootDir, module string) (string, error) {
+	if ref := os.Getenv(FromRefEnvVar); ref != "" {
+		return ref, nil
+	}
+
+	out, err := git(rootDir, "tag", "--list", module+"-v*", "--sort=-v:refname")
+	if err != nil {
+		return "", err
+	}
+
+	tags := strings.Fields(string(out))
+	if len(tags) == 0 {
+		return "", nil
+	}
+
+	return tags[0], nil
+}
+
+// Export writes the modules and examples of the repository at ref into a temporary directory,
+// removed through t.Cleanup, and returns its path. The working tree and its git metadata are not
+// touched.
+func Export(t *testing.T, rootDir, ref string) string {
+	dir := t.TempDir()
+
+	archive, err := git(rootDir, "archive", "--format=tar", ref, "modules", "examples")
+	require.NoError(t, err, "Failed to archive %s", ref)
+
+	require.NoError(t, extract(bytes.NewReader(archive), dir), "Failed to extract %s", ref)
+
+	t.Logf("📂 Exported %s to: %s", ref, dir)
+
+	return dir
+}
+
+// newOptions builds the Terraform options of one side of the upgrade.
+func newOptions(t *testing.T, src helper.Source, cfg Config, awsCtx harness.Context, opts ...helper.SetupOption) *terraform.Options {
+	opts = append(opts, awsCtx.TerraformOption(), helper.WithNoColor())
+
+	if len(cfg.VarFiles) > 0 {
+		opts = append(opts, helper.WithVarFiles(cfg.VarFiles...))
+	}
+
+	if len(cfg.Vars) > 0 {
+		opts = append(opts, helper.WithVars(cfg.Vars))
+	}
+
+	return helper.NewTerraformOptions(t, src, opts...)
+}
+
+// copyState copies the local state of the release example into the working tree example, so the
+// working tree plans against the applied resources while the release keeps the state to destroy.
+func copyState(t *testing.T, fromDir, toDir string) {
+	state, err := os.ReadFile(filepath.Join(fromDir, stateFileName))
+	require.NoError(t, err, "Failed to read the state of the release example")
+
+	require.NoError(t, os.WriteFile(filepath.Join(toDir, stateFileName), state, 0o600),
+		"Failed to write the state into the working tree example")
+}
+
+// extract writes the regular files and directories of a tar stream under dir.
+func extract(r io.Reader, dir string) error {
+	archive := tar.NewReader(r)
+
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dir, filepath.Clean(header.Name))
+		if !strings.HasPrefix(target, filepath.Clean(dir)+string(filepath.Separator)) {
+			return fmt.Errorf("archive entry %q escapes %s", header.Name, dir)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeFile(target, archive, os.FileMode(header.Mode).Perm()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// writeFile writes the content of r to path, creating its parent directory when needed.
+func writeFile(path string, r io.Reader, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
+
+// git runs a git command in dir and returns its standard output.
+func git(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+
+	return out, nil
+}
@@ -0,0 +1,198 @@
+// Package validation runs table-driven negative tests against the variable validation blocks of a
+// module: every case plans the module with an invalid value and expects the exact error_message.
+package validation
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/repo"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+// invalidValueSummary is the summary of the diagnostic Terraform reports for a failed validation.
+const invalidValueSummary = "Invalid value for variable"
+
+// diagnosticDecorations matches the box drawing Terraform prints around diagnostics, which is
+// removed together with the line wrapping before comparing messages.
+var diagnosticDecorations = regexp.MustCompile(`[│╷╵]`)
+
+// Case is a set of invalid values expected to fail one validation block.
+type Case struct {
+	// Name identifies the case in its subtest, "<variable>/<name>".
+	Name string
+
+	// Variable is the variable whose validation must fail.
+	Variable string
+
+	// Vars holds the invalid values, merged over Matrix.Vars.
+	Vars map[string]interface{}
+
+	// ErrorMessage is the error_message of the validation block the values violate, verbatim.
+	ErrorMessage string
+}
+
+// Matrix is the negative test table of a module.
+type Matrix struct {
+	// Module is the name of the module under modules/.
+	Module string
+
+	// Vars holds valid values for the module's required variables, shared by every case.
+	Vars map[string]interface{}
+
+	// Cases are planned in parallel subtests.
+	Cases []Case
+}
+
+// Run plans the module once per case and requires the plan to fail with the case's error message.
+// A final "coverage" subtest fails for every validation block of the module that no case expects.
+func Run(t *testing.T, matrix Matrix) {
+	t.Helper()
+
+	dirs, err := repo.NewTFSourcesDir()
+	require.NoError(t, err, "Failed to get Terraform sources directory")
+
+	moduleDir := dirs.GetModulesDir(matrix.Module)
+
+	variables, err := repo.ParseVariables(moduleDir)
+	require.NoError(t, err, "Failed to parse the variables of module %s", matrix.Module)
+
+	for _, c := range matrix.Cases {
+		c := c
+
+		requireDeclaredMessage(t, variables, c)
+
+		t.Run(c.Variable+"/"+c.Name, func(t *testing.T) {
+			t.Parallel()
+			planCase(t, moduleDir, matrix.Vars, c)
+		})
+	}
+
+	t.Run("coverage", func(t *testing.T) {
+		uncovered := Uncovered(dirs.GetRootDir(), variables, matrix.Cases)
+		for _, block := range uncovered {
+			t.Errorf("❌ No negative case for %s", block)
+		}
+
+		if len(uncovered) == 0 {
+			t.Logf("✅ Every validation block of module %s has a negative case", matrix.Module)
+		}
+	})
+}
+
+// Uncovered describes the validation blocks that no case expects, sorted by location. Paths are
+// relative to rootDir.
+func Uncovered(rootDir string, variables map[string]repo.Variable, cases []Case) []string {
+	covered := map[string]bool{}
+	for _, c := range cases {
+		covered[c.Variable+"\x00"+c.ErrorMessage] = true
+	}
+
+	type block struct {
+		file string
+		line int
+		text string
+	}
+
+	var blocks []block
+
+	for name, variable := range variables {
+		for _, v := range variable.Validations {
+			if covered[name+"\x00"+v.ErrorMessage] {
+				continue
+			}
+
+			file := v.Range.Filename
+			if rel, err := filepath.Rel(rootDir, file); err == nil {
+				file = rel
+			}
+
+			blocks = append(blocks, block{
+				file: file,
+				line: v.Range.Start.Line,
+				text: fmt.Sprintf("%s:%d: validation of %q: %q", file, v.Range.Start.Line, name, v.ErrorMessage),
+			})
+		}
+	}
+
+	sort.Slice(blocks, func(i, j int) bool {
+		if blocks[i].file != blocks[j].file {
+			return blocks[i].file < blocks[j].file
+		}
+
+		return blocks[i].line < blocks[j].line
+	})
+
+	descriptions := make([]string, len(blocks))
+	for i, b := range blocks {
+		descriptions[i] = b.text
+	}
+
+	return descriptions
+}
+
+// requireDeclaredMessage fails the test unless the case expects a message that one of the validation
+// blocks of its variable declares, so a reworded message cannot leave a case silently stale.
+func requireDeclaredMessage(t *testing.T, variables map[string]repo.Variable, c Case) {
+	t.Helper()
+
+	variable, ok := variables[c.Variable]
+	require.Truef(t, ok, "Case %s/%s targets variable %q, which the module does not declare", c.Variable, c.Name, c.Variable)
+
+	messages := make([]string, 0, len(variable.Validations))
+	for _, v := range variable.Validations {
+		if v.ErrorMessage == c.ErrorMessage {
+			return
+		}
+
+		messages = append(messages, v.ErrorMessage)
+	}
+
+	require.Failf(t, "Unknown error message",
+		"Case %s/%s expects %q, but the validations of %q declare %q", c.Variable, c.Name, c.ErrorMessage, c.Variable, messages)
+}
+
+// planCase plans the module with the case's values and checks the reported validation error.
+func planCase(t *testing.T, moduleDir string, baseVars map[string]interface{}, c Case) {
+	vars := make(map[string]interface{}, len(baseVars)+len(c.Vars))
+	for name, value := range baseVars {
+		vars[name] = value
+	}
+
+	for name, value := range c.Vars {
+		vars[name] = value
+	}
+
+	terraformOptions := helper.NewTerraformOptions(t, helper.ModuleSource(moduleDir),
+		helper.WithWorkspaceCopy(),
+		helper.WithVars(vars),
+		helper.WithNoColor(),
+	)
+
+	t.Logf("🔍 Terraform Module Directory: %s", terraformOptions.TerraformDir)
+
+	_, err := helper.InitE(t, terraformOptions)
+	require.NoError(t, err, "Terraform init failed")
+
+	out, err := terraform.PlanE(t, terraformOptions)
+	require.Error(t, err, "Plan should fail the validation of %q", c.Variable)
+
+	output := normalizeDiagnostics(out + "\n" + err.Error())
+	require.Contains(t, output, invalidValueSummary, "Plan should fail on an invalid variable value")
+	require.Contains(t, output, normalizeDiagnostics(c.ErrorMessage),
+		"Plan should report the error message of the validation of %q", c.Variable)
+
+	t.Logf("✅ Plan failed the validation of %q as expected", c.Variable)
+}
+
+// normalizeDiagnostics removes the diagnostic box drawing and collapses whitespace, so a message
+// matches regardless of how Terraform wrapped it.
+func normalizeDiagnostics(text string) string {
+	return strings.Join(strings.Fields(diagnosticDecorations.ReplaceAllString(text, " ")), " ")
+}
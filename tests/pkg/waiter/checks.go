@@ -0,0 +1,209 @@
+package waiter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/codeartifact"
+	codeartifacttypes "github.com/aws/aws-sdk-go-v2/service/codeartifact/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// stillExists is the state reported for a resource that can still be described.
+const stillExists = "still exists"
+
+// CodeArtifactAPI is the subset of the CodeArtifact client used by the waiters.
+type CodeArtifactAPI interface {
+	DescribeDomain(ctx context.Context, params *codeartifact.DescribeDomainInput, optFns ...func(*codeartifact.Options)) (*codeartifact.DescribeDomainOutput, error)
+	DescribeRepository(ctx context.Context, params *codeartifact.DescribeRepositoryInput, optFns ...func(*codeartifact.Options)) (*codeartifact.DescribeRepositoryOutput, error)
+}
+
+// S3API is the subset of the S3 client used by the waiters.
+type S3API interface {
+	HeadBucket(ctx context.Context, params *s3.HeadBucketInput, optFns ...func(*s3.Options)) (*s3.HeadBucketOutput, error)
+}
+
+// KMSAPI is the subset of the KMS client used by the waiters.
+type KMSAPI interface {
+	DescribeKey(ctx context.Context, params *kms.DescribeKeyInput, optFns ...func(*kms.Options)) (*kms.DescribeKeyOutput, error)
+}
+
+// CloudWatchLogsAPI is the subset of the CloudWatch Logs client used by the waiters.
+type CloudWatchLogsAPI interface {
+	DescribeLogGroups(ctx context.Context, params *cloudwatchlogs.DescribeLogGroupsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeLogGroupsOutput, error)
+}
+
+// IAMAPI is the subset of the IAM client used by the waiters.
+type IAMAPI interface {
+	GetRole(ctx context.Context, params *iam.GetRoleInput, optFns ...func(*iam.Options)) (*iam.GetRoleOutput, error)
+}
+
+// CodeArtifactDomainDeleted checks that a CodeArtifact domain no longer exists.
+func CodeArtifactDomainDeleted(client CodeArtifactAPI, domain, owner string) Check {
+	return Check{
+		Kind: "CodeArtifact domain",
+		ID:   domain,
+		Probe: func(ctx context.Context) (bool, string, error) {
+			input := &codeartifact.DescribeDomainInput{Domain: aws.String(domain)}
+			if owner != "" {
+				input.DomainOwner = aws.String(owner)
+			}
+
+			out, err := client.DescribeDomain(ctx, input)
+
+			var notFound *codeartifacttypes.ResourceNotFoundException
+			if errors.As(err, &notFound) {
+				return true, "deleted", nil
+			}
+
+			if err != nil {
+				return false, "", err
+			}
+
+			if out.Domain != nil && out.Domain.Status == codeartifacttypes.DomainStatusDeleted {
+				return true, "deleted", nil
+			}
+
+			return false, stillExists, nil
+		},
+	}
+}
+
+// CodeArtifactRepositoryDeleted checks that a CodeArtifact repository no longer exists.
+func CodeArtifactRepositoryDeleted(client CodeArtifactAPI, domain, owner, repository string) Check {
+	return Check{
+		Kind: "CodeArtifact repository",
+		ID:   domain + "/" + repository,
+		Probe: func(ctx context.Context) (bool, string, error) {
+			input := &codeartifact.DescribeRepositoryInput{
+				Domain:     aws.String(domain),
+				Repository: aws.String(repository),
+			}
+			if owner != "" {
+				input.DomainOwner = aws.String(owner)
+			}
+
+			_, err := client.DescribeRepository(ctx, input)
+
+			var notFound *codeartifacttypes.ResourceNotFoundException
+			if errors.As(err, &notFound) {
+				return true, "deleted", nil
+			}
+
+			if err != nil {
+				return false, "", err
+			}
+
+			return false, stillExists, nil
+		},
+	}
+}
+
+// S3BucketDeleted checks that an S3 bucket no longer exists.
+func S3BucketDeleted(client S3API, bucket string) Check {
+	return Check{
+		Kind: "S3 bucket",
+		ID:   bucket,
+		Probe: func(ctx context.Context) (bool, string, error) {
+			_, err := client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String(bucket)})
+
+			var notFound *s3types.NotFound
+			var noSuchBucket *s3types.NoSuchBucket
+			if errors.As(err, &notFound) || errors.As(err, &noSuchBucket) {
+				return true, "deleted", nil
+			}
+
+			if err != nil {
+				return false, "", err
+			}
+
+			return false, stillExists, nil
+		},
+	}
+}
+
+// KMSKeyPendingDeletion checks that a KMS key is scheduled for deletion (or already deleted).
+// KMS keys are never deleted immediately, so PendingDeletion is the expected state after destroy.
+func KMSKeyPendingDeletion(client KMSAPI, keyID string) Check {
+	return Check{
+		Kind: "KMS key",
+		ID:   keyID,
+		Probe: func(ctx context.Context) (bool, string, error) {
+			out, err := client.DescribeKey(ctx, &kms.DescribeKeyInput{KeyId: aws.String(keyID)})
+
+			var notFound *kmstypes.NotFoundException
+			if errors.As(err, &notFound) {
+				return true, "deleted", nil
+			}
+
+			if err != nil {
+				return false, "", err
+			}
+
+			if out.KeyMetadata == nil {
+				return false, "no key metadata", nil
+			}
+
+			state := out.KeyMetadata.KeyState
+			if state == kmstypes.KeyStatePendingDeletion {
+				return true, string(state), nil
+			}
+
+			return false, fmt.Sprintf("key state is %s", state), nil
+		},
+	}
+}
+
+// LogGroupDeleted checks that a CloudWatch log group no longer exists.
+func LogGroupDeleted(client CloudWatchLogsAPI, name string) Check {
+	return Check{
+		Kind: "CloudWatch log group",
+		ID:   name,
+		Probe: func(ctx context.Context) (bool, string, error) {
+			out, err := client.DescribeLogGroups(ctx, &cloudwatchlogs.DescribeLogGroupsInput{
+				LogGroupNamePrefix: aws.String(name),
+			})
+			if err != nil {
+				return false, "", err
+			}
+
+			for _, group := range out.LogGroups {
+				if aws.ToString(group.LogGroupName) == name {
+					return false, stillExists, nil
+				}
+			}
+
+			return true, "deleted", nil
+		},
+	}
+}
+
+// IAMRoleDeleted checks that an IAM role no longer exists.
+func IAMRoleDeleted(client IAMAPI, name string) Check {
+	return Check{
+		Kind: "IAM role",
+		ID:   name,
+		Probe: func(ctx context.Context) (bool, string, error) {
+			_, err := client.GetRole(ctx, &iam.GetRoleInput{RoleName: aws.String(name)})
+
+			var noSuchEntity *iamtypes.NoSuchEntityException
+			if errors.As(err, &noSuchEntity) {
+				return true, "deleted", nil
+			}
+
+			if err != nil {
+				return false, "", err
+			}
+
+			return false, stillExists, nil
+		},
+	}
+}
@@ -0,0 +1,77 @@
+package waiter
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/plan"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	// destructionCompletePattern matches the line `terraform destroy` prints when a resource is gone.
+	destructionCompletePattern = regexp.MustCompile(`(?m)^(.+?): Destruction complete after`)
+
+	// ansiEscapePattern matches the color codes of Terraform output run without -no-color.
+	ansiEscapePattern = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+	// instanceKeyPattern matches the instance keys of a resource or module address, e.g. [0] or ["a"].
+	instanceKeyPattern = regexp.MustCompile(`\[[^\]]*\]`)
+)
+
+// DestructionOrder returns the addresses of the resources in the order `terraform destroy`
+// reported them destroyed.
+func DestructionOrder(output string) []string {
+	var order []string
+
+	for _, match := range destructionCompletePattern.FindAllStringSubmatch(ansiEscapePattern.ReplaceAllString(output, ""), -1) {
+		order = append(order, match[1])
+	}
+
+	return order
+}
+
+// RequireDestroyOrder fails the test unless every managed resource of the state was reported
+// destroyed in output, and before every resource it depends on.
+func RequireDestroyOrder(t *testing.T, state *plan.State, output string) {
+	t.Helper()
+
+	position := map[string]int{}
+	for i, address := range DestructionOrder(output) {
+		position[address] = i
+	}
+
+	resources := state.ManagedResources()
+
+	var missing, violations []string
+
+	for _, r := range resources {
+		if _, ok := position[r.Address]; !ok {
+			missing = append(missing, r.Address)
+		}
+	}
+
+	require.Emptyf(t, missing, "Every resource of the state should be reported destroyed")
+
+	for _, r := range resources {
+		for _, dependency := range r.DependsOn {
+			for _, d := range resources {
+				if configAddress(d.Address) != dependency || position[r.Address] < position[d.Address] {
+					continue
+				}
+
+				violations = append(violations, fmt.Sprintf("%s was destroyed after %s, which it depends on", r.Address, d.Address))
+			}
+		}
+	}
+
+	require.Emptyf(t, violations, "Resources should be destroyed in dependency order")
+
+	t.Logf("✅ %d resources destroyed in dependency order", len(resources))
+}
+
+// configAddress returns the configuration address of a resource instance, without instance keys.
+func configAddress(address string) string {
+	return instanceKeyPattern.ReplaceAllString(address, "")
+}
@@ -0,0 +1,126 @@
+package waiter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/helper"
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/plan"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/codeartifact"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+// Clients holds the AWS clients the waiters poll.
+type Clients struct {
+	CodeArtifact CodeArtifactAPI
+	S3           S3API
+	KMS          KMSAPI
+	Logs         CloudWatchLogsAPI
+	IAM          IAMAPI
+}
+
+// NewClients builds the waiter clients from an AWS SDK configuration, talking to the same
+// endpoint as the Terraform run described by options.
+func NewClients(cfg aws.Config, options *terraform.Options) Clients {
+	return Clients{
+		CodeArtifact: codeartifact.NewFromConfig(cfg),
+		S3:           s3.NewFromConfig(cfg, helper.S3Options(options)),
+		KMS:          kms.NewFromConfig(cfg),
+		Logs:         cloudwatchlogs.NewFromConfig(cfg),
+		IAM:          iam.NewFromConfig(cfg),
+	}
+}
+
+// ChecksFromState returns a deletion check for every resource of the state that has a waiter:
+// CodeArtifact domains and repositories, S3 buckets, KMS keys, CloudWatch log groups and IAM roles.
+func (c Clients) ChecksFromState(state *plan.State) []Check {
+	var checks []Check
+
+	for _, r := range state.ManagedResources() {
+		switch r.Type {
+		case "aws_codeartifact_domain":
+			if domain, ok := r.StringValue("domain"); ok {
+				owner, _ := r.StringValue("owner")
+				checks = append(checks, CodeArtifactDomainDeleted(c.CodeArtifact, domain, owner))
+			}
+		case "aws_codeartifact_repository":
+			domain, hasDomain := r.StringValue("domain")
+			repository, hasRepository := r.StringValue("repository")
+			if hasDomain && hasRepository {
+				owner, _ := r.StringValue("domain_owner")
+				checks = append(checks, CodeArtifactRepositoryDeleted(c.CodeArtifact, domain, owner, repository))
+			}
+		case "aws_s3_bucket":
+			if bucket, ok := r.StringValue("bucket"); ok {
+				checks = append(checks, S3BucketDeleted(c.S3, bucket))
+			}
+		case "aws_kms_key":
+			if keyID, ok := r.StringValue("key_id"); ok {
+				checks = append(checks, KMSKeyPendingDeletion(c.KMS, keyID))
+			}
+		case "aws_cloudwatch_log_group":
+			if name, ok := r.StringValue("name"); ok {
+				checks = append(checks, LogGroupDeleted(c.Logs, name))
+			}
+		case "aws_iam_role":
+			if name, ok := r.StringValue("name"); ok {
+				checks = append(checks, IAMRoleDeleted(c.IAM, name))
+			}
+		}
+	}
+
+	return checks
+}
+
+// DestroyAndWait records the resources in the state of options.TerraformDir, runs `terraform destroy`
+// and waits with DefaultConfig until AWS reports every recorded resource as deleted. It replaces
+// fixed sleeps after destroy and fails the test with the list of resources that survived.
+func DestroyAndWait(t *testing.T, options *terraform.Options, region string) {
+	t.Helper()
+
+	destroyAndWait(t, options, region)
+}
+
+// DestroyInOrderAndWait destroys like DestroyAndWait and also fails the test unless every resource
+// was destroyed before the resources it depends on, as recorded in the state.
+func DestroyInOrderAndWait(t *testing.T, options *terraform.Options, region string) {
+	t.Helper()
+
+	state, output := destroyAndWait(t, options, region)
+	if state == nil {
+		t.Log("⚠️ No state was read before destroy, the destroy order will not be verified")
+		return
+	}
+
+	RequireDestroyOrder(t, state, output)
+}
+
+// destroyAndWait implements DestroyAndWait, returning the state read before destroy, if any, and
+// the output of `terraform destroy`.
+func destroyAndWait(t *testing.T, options *terraform.Options, region string) (*plan.State, string) {
+	t.Helper()
+
+	state, err := plan.ShowStateE(t, options)
+	if err != nil {
+		t.Logf("⚠️ Could not read the state before destroy, deletion will not be verified: %v", err)
+	}
+
+	output := terraform.Destroy(t, options)
+
+	if state == nil {
+		return nil, output
+	}
+
+	awsCfg, err := helper.LoadAWSConfig(context.Background(), options, region)
+	require.NoError(t, err, "Failed to load AWS configuration")
+
+	Wait(t, DefaultConfig, NewClients(awsCfg, options).ChecksFromState(state)...)
+
+	return state, output
+}
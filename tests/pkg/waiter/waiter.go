@@ -0,0 +1,147 @@
+package waiter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Check polls a single resource until it reaches the state expected after `terraform destroy`.
+type Check struct {
+	Kind string // The kind of resource, e.g. "S3 bucket".
+	ID   string // The identifier of the resource, e.g. the bucket name.
+
+	// Probe reports whether the resource reached its expected state and describes the observed state.
+	// Errors are treated as transient and retried until the deadline.
+	Probe func(ctx context.Context) (done bool, state string, err error)
+}
+
+// String returns a human-readable description of the checked resource.
+func (c Check) String() string {
+	return c.Kind + " " + c.ID
+}
+
+// Config controls how often resources are polled and for how long.
+type Config struct {
+	Timeout         time.Duration // The overall deadline for every check to complete.
+	InitialInterval time.Duration // The delay before the second poll.
+	MaxInterval     time.Duration // The upper bound of the delay between polls.
+	Multiplier      float64       // The factor applied to the delay after each poll.
+}
+
+// DefaultConfig polls quickly at first and backs off to every 30 seconds, for up to 10 minutes.
+var DefaultConfig = Config{
+	Timeout:         10 * time.Minute,
+	InitialInterval: 2 * time.Second,
+	MaxInterval:     30 * time.Second,
+	Multiplier:      2,
+}
+
+// Survivor is a resource that did not reach its expected state before the deadline.
+type Survivor struct {
+	Check Check  // The check that did not complete.
+	State string // The last observed state.
+	Err   error  // The last error returned by the probe, if any.
+}
+
+// SurvivorsError reports every resource that survived the deadline.
+type SurvivorsError struct {
+	Timeout   time.Duration
+	Survivors []Survivor
+}
+
+// Error lists the surviving resources with their last observed state.
+func (e *SurvivorsError) Error() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%d resource(s) survived destroy after %s:", len(e.Survivors), e.Timeout)
+
+	for _, s := range e.Survivors {
+		fmt.Fprintf(&b, "\n  - %s: %s", s.Check, s.State)
+
+		if s.Err != nil {
+			fmt.Fprintf(&b, " (last error: %v)", s.Err)
+		}
+	}
+
+	return b.String()
+}
+
+// Wait polls every check until all of them complete, failing the test with the list of surviving
+// resources when cfg.Timeout elapses first.
+func Wait(t *testing.T, cfg Config, checks ...Check) {
+	t.Helper()
+
+	if len(checks) == 0 {
+		return
+	}
+
+	t.Logf("⏳ Waiting up to %s for %d resource(s) to be deleted...", cfg.Timeout, len(checks))
+
+	start := time.Now()
+	if err := WaitE(context.Background(), cfg, checks...); err != nil {
+		t.Fatalf("❌ %v", err)
+	}
+
+	t.Logf("✅ All %d resource(s) deleted after %s", len(checks), time.Since(start).Round(time.Second))
+}
+
+// WaitE polls every check with exponential backoff until all of them complete. It returns a
+// *SurvivorsError when cfg.Timeout elapses or ctx is cancelled first.
+func WaitE(ctx context.Context, cfg Config, checks ...Check) error {
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	pending := make([]Survivor, 0, len(checks))
+	for _, check := range checks {
+		pending = append(pending, Survivor{Check: check, State: "not polled yet"})
+	}
+
+	interval := cfg.InitialInterval
+
+	for {
+		remaining := pending[:0]
+
+		for _, p := range pending {
+			done, state, err := p.Check.Probe(ctx)
+			if done {
+				continue
+			}
+
+			switch {
+			case state != "":
+				p.State = state
+			case err != nil:
+				p.State = "could not be described"
+			}
+
+			p.Err = err
+			remaining = append(remaining, p)
+		}
+
+		pending = remaining
+		if len(pending) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return &SurvivorsError{Timeout: cfg.Timeout, Survivors: pending}
+		case <-time.After(interval):
+		}
+
+		interval = nextInterval(interval, cfg)
+	}
+}
+
+// nextInterval returns the delay following interval, capped at cfg.MaxInterval.
+func nextInterval(interval time.Duration, cfg Config) time.Duration {
+	next := time.Duration(float64(interval) * cfg.Multiplier)
+	if next <= 0 || next > cfg.MaxInterval {
+		return cfg.MaxInterval
+	}
+
+	return next
+}
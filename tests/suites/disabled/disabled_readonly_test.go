@@ -0,0 +1,23 @@
+//go:build unit && readonly
+
+package disabled
+
+import (
+	"testing"
+
+	"github.com/Excoriate/terraform-aws-codeartifact/tests/pkg/invariant"
+)
+
+// TestDisabledModeOnAllModulesWhenIsEnabledIsFalse verifies that every module and example, planned
+// disabled, creates nothing, reads no data source that needs credentials, and exposes only null,
+// false or empty outputs.
+func TestDisabledModeOnAllModulesWhenIsEnabledIsFalse(t *testing.T) {
+	t.Parallel()
+
+	invariant.RunDisabled(t, invariant.DisabledConfig{
+		Vars: map[string]map[string]interface{}{
+			// The alias is validated before the module is evaluated, so it needs a valid value
+			"foundation": {"kms_key_alias": "alias/disabled"},
+		},
+	})
+}